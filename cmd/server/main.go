@@ -1,22 +1,41 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"strings"
 	"time"
 
 	docs "lam-phuong-api/docs" // Import docs for Swagger
+	"lam-phuong-api/internal/audit"
+	"lam-phuong-api/internal/authserver"
+	"lam-phuong-api/internal/authz"
 	"lam-phuong-api/internal/config"
 	"lam-phuong-api/internal/email"
+	"lam-phuong-api/internal/job"
 	jobCategory "lam-phuong-api/internal/jobCategory"
 	jobType "lam-phuong-api/internal/jobType"
 	"lam-phuong-api/internal/location"
+	"lam-phuong-api/internal/logger"
 	productGroup "lam-phuong-api/internal/productGroup"
+	"lam-phuong-api/internal/refreshtoken"
+	"lam-phuong-api/internal/replication"
+	"lam-phuong-api/internal/revocation"
 	"lam-phuong-api/internal/server"
+	"lam-phuong-api/internal/slugindex"
+	"lam-phuong-api/internal/sync"
 	"lam-phuong-api/internal/user"
 )
 
+// locationSyncCron is the default replication schedule for the locations
+// table: every 5 minutes.
+const locationSyncCron = "*/5 * * * *"
+
+// slugIndexReconcileInterval is how often the shared slug-uniqueness index
+// re-warms itself from Airtable, to pick up slugs changed outside the API.
+const slugIndexReconcileInterval = 5 * time.Minute
+
 // @title           Lam Phuong API
 // @version         1.0
 // @description     API for managing locations
@@ -50,6 +69,18 @@ func main() {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
 
+	// Apply the configured log level before anything starts logging.
+	logger.SetLevel(cfg.Server.LogLevel)
+
+	// Configure the argon2id cost parameters used for new password hashes.
+	user.SetArgon2Params(user.Argon2Params{
+		Memory:      cfg.Auth.Argon2.MemoryKB,
+		Iterations:  cfg.Auth.Argon2.Iterations,
+		Parallelism: cfg.Auth.Argon2.Parallelism,
+		SaltLength:  user.DefaultArgon2Params.SaltLength,
+		KeyLength:   user.DefaultArgon2Params.KeyLength,
+	})
+
 	// Configure Swagger host/schemes so deployed instances don't default to localhost
 	swaggerHost := strings.TrimSpace(os.Getenv("SWAGGER_HOST"))
 	if swaggerHost == "" {
@@ -85,6 +116,29 @@ func main() {
 	locationRepo := location.NewAirtableRepository(airtableClient, cfg.Airtable.LocationsTableName)
 	locationHandler := location.NewHandler(locationRepo)
 
+	// Warm the location cache and keep it fresh on a cron schedule so
+	// AirtableRepository.List/Get/GetBySlug don't hit Airtable on every call.
+	if err := locationRepo.Warmup(context.Background()); err != nil {
+		log.Printf("Warning: initial location cache warmup failed: %v", err)
+	}
+	syncRunner := sync.NewRunner()
+	if err := syncRunner.Register(sync.ReplicationPolicy{
+		Table:    cfg.Airtable.LocationsTableName,
+		CronExpr: locationSyncCron,
+		Enabled:  true,
+	}, locationRepo.Warmup); err != nil {
+		log.Printf("Warning: failed to register location sync policy: %v", err)
+	}
+	syncRunner.Start()
+	syncHandler := sync.NewHandler(syncRunner)
+
+	// Audit log: Airtable-backed, recording every mutating
+	// request plus field-level diffs from repositories that know how to
+	// produce them.
+	auditRecorder := audit.NewAirtableRecorder(airtableClient, cfg.Airtable.AuditLogTableName)
+	auditHandler := audit.NewHandler(auditRecorder)
+	locationRepo.SetAuditRecorder(auditRecorder)
+
 	productGroupRepo := productGroup.NewAirtableRepository(airtableClient, cfg.Airtable.ProductGroupsTableName)
 	productGroupHandler := productGroup.NewHandler(productGroupRepo)
 
@@ -93,12 +147,125 @@ func main() {
 
 	jobTypeRepo := jobType.NewAirtableRepository(airtableClient, cfg.Airtable.JobTypesTableName)
 	jobTypeHandler := jobType.NewHandler(jobTypeRepo)
+	jobTypeRepo.SetAuditRecorder(auditRecorder)
+
+	// Shared slug-uniqueness index: avoids an O(N) repo.List call on every
+	// create by keeping slug->recordID maps warm per table, updated
+	// write-through on Create/DeleteBySlug and re-warmed periodically to
+	// catch slugs changed outside the API. productGroup has no slug field
+	// and is not part of this index.
+	listJobCategorySlugs := func() map[string]string {
+		items := jobCategoryRepo.List(context.Background())
+		out := make(map[string]string, len(items))
+		for _, jc := range items {
+			out[jc.Slug] = jc.ID
+		}
+		return out
+	}
+	listJobTypeSlugs := func() map[string]string {
+		items := jobTypeRepo.List(context.Background())
+		out := make(map[string]string, len(items))
+		for _, jt := range items {
+			out[jt.Slug] = jt.ID
+		}
+		return out
+	}
+	listLocationSlugs := func() map[string]string {
+		items := locationRepo.List(context.Background())
+		out := make(map[string]string, len(items))
+		for _, loc := range items {
+			out[loc.Slug] = loc.ID
+		}
+		return out
+	}
+
+	slugIdx := slugindex.New()
+	slugIdx.Warmup("jobcategory", listJobCategorySlugs)
+	slugIdx.Warmup("jobtype", listJobTypeSlugs)
+	slugIdx.Warmup("location", listLocationSlugs)
+	jobCategoryRepo.SetSlugIndex(slugIdx)
+	jobTypeRepo.SetSlugIndex(slugIdx)
+	locationRepo.SetSlugIndex(slugIdx)
+	jobCategoryHandler.SetSlugIndex(slugIdx)
+	jobTypeHandler.SetSlugIndex(slugIdx)
+	locationHandler.SetSlugIndex(slugIdx)
+
+	slugReconciler := slugindex.NewReconciler(slugIdx, slugIndexReconcileInterval)
+	slugReconciler.RegisterTable("jobcategory", listJobCategorySlugs)
+	slugReconciler.RegisterTable("jobtype", listJobTypeSlugs)
+	slugReconciler.RegisterTable("location", listLocationSlugs)
+	slugReconciler.Start()
+
+	// Async job subsystem. Supported routes run as background jobs when
+	// cfg.Jobs.DefaultAsync is set, or per-request via ?async=true/false
+	// or a "Prefer: respond-async=false" opt-out header.
+	jobService := job.NewService(job.NewInMemoryStore(), cfg.Jobs.MaxWorkers)
+	jobHandler := job.NewHandler(jobService)
+	locationHandler.SetJobService(jobService)
+	locationHandler.SetDefaultAsync(cfg.Jobs.DefaultAsync)
+	jobTypeHandler.SetJobService(jobService)
+	jobTypeHandler.SetDefaultAsync(cfg.Jobs.DefaultAsync)
+	jobCategoryHandler.SetJobService(jobService)
+	jobCategoryHandler.SetDefaultAsync(cfg.Jobs.DefaultAsync)
+
+	// Replication engine: mirrors Create/DeleteBySlug mutations from
+	// jobcategory, jobtype, location and user into any secondary datastore
+	// (Postgres, another Airtable base, S3 snapshots) a policy points at.
+	// No real client for those destinations exists in this repo yet, so
+	// the registered Apply funcs just log the event; swapping in a real
+	// writer only touches this block.
+	replicationEngine := replication.NewEngine(replication.NewInMemoryPolicyStore(), replication.NewInMemoryTargetStore(), cfg.Jobs.MaxWorkers)
+	for _, table := range []string{"jobcategory", "jobtype", "location", "user"} {
+		table := table
+		replicationEngine.RegisterApply(table, func(ctx context.Context, target replication.Target, event replication.Event) error {
+			log := logger.FromContext(ctx)
+			log.Info().
+				Str("action", string(event.Action)).
+				Str("table", table).
+				Str("resource_id", event.ResourceID).
+				Str("target_id", target.ID).
+				Str("target_type", string(target.Type)).
+				Msg("replication: mirroring event")
+			return nil
+		})
+	}
+	replicationEngine.Start()
+	replicationHandler := replication.NewHandler(replicationEngine)
+	jobCategoryRepo.SetReplicator(replicationEngine)
+	jobTypeRepo.SetReplicator(replicationEngine)
+	locationRepo.SetReplicator(replicationEngine)
 
 	userRepo := user.NewAirtableRepository(airtableClient, cfg.Airtable.UsersTableName)
+	userRepo.SetReplicator(replicationEngine)
 
 	// Create user handler with JWT configuration
 	tokenExpiry := time.Duration(cfg.Auth.TokenExpiry) * time.Hour
 	userHandler := user.NewHandler(userRepo, cfg.Auth.JWTSecret, tokenExpiry)
+	userHandler.SetAuthenticators(buildAuthenticators(cfg, userRepo))
+	userHandler.SetOAuthProviders(buildOAuthProviders(cfg))
+	userHandler.SetRefreshTokenRepository(refreshtoken.NewInMemoryRepository())
+	userHandler.SetRevocationStore(revocation.NewInMemoryStore())
+	user.RegisterRevoker(userHandler)
+
+	// Pluggable OAuth2/OIDC authorization server: exposes /oauth/authorize,
+	// /oauth/token, /oauth/userinfo and /.well-known/openid-configuration
+	// alongside the legacy /auth/login, signing its own tokens with a
+	// generated RS256 key pair rather than the HS256 jwtSecret. Defaults to
+	// LocalPasswordProvider so it authenticates the same accounts; swap in
+	// an OIDCProvider once a real external-IdP verifier exists.
+	authServerKeySet, err := authserver.NewKeySet()
+	if err != nil {
+		log.Fatalf("failed to generate auth server key set: %v", err)
+	}
+	authServerHandler := authserver.NewHandler(
+		authserver.NewLocalPasswordProvider(userRepo),
+		authserver.NewInMemoryAuthRequestStore(),
+		authserver.NewInMemoryClientStore(buildAuthServerClients(cfg)),
+		authServerKeySet,
+		cfg.Email.BaseURL,
+		tokenExpiry,
+	)
+	user.RegisterJWKSVerifier(authServerHandler.KeySet())
 
 	// Initialize email service (Gmail API)
 	var emailService *email.Service
@@ -124,7 +291,32 @@ func main() {
 		}
 	}
 
-	router := server.NewRouter(locationHandler, productGroupHandler, jobCategoryHandler, jobTypeHandler, userHandler, emailHandler, cfg.Auth.JWTSecret)
+	// Runtime config admin API: lets an admin edit email settings, table
+	// names, token expiry and log level. Only the log level and the email
+	// base URL are applied to running components below; the other edited
+	// fields are persisted as overrides and take effect at the next start.
+	// Airtable credentials and the JWT secret are not editable here at all.
+	configHandler := config.NewHandler(config.AdminStore())
+	config.Subscribe(func(updated *config.Config) {
+		logger.SetLevel(updated.Server.LogLevel)
+		if emailService != nil && updated.Email.BaseURL != "" {
+			userHandler.SetEmailService(emailService, updated.Email.BaseURL)
+		}
+		log.Printf("Config updated via admin API")
+	})
+
+	// RBAC policy: falls back to the built-in defaults unless RBAC_POLICY_PATH
+	// points at a YAML policy file to load (and hot-reload via Store.Reload).
+	var policyLoader authz.PolicyLoader
+	if policyPath := strings.TrimSpace(os.Getenv("RBAC_POLICY_PATH")); policyPath != "" {
+		policyLoader = authz.YAMLFileLoader{Path: policyPath}
+	}
+	authzStore := authz.NewStore(policyLoader, authz.DefaultPolicies())
+	authzHandler := authz.NewHandler(authzStore)
+
+	// No standalone email queue handler is constructed above; NewRouter
+	// already treats a nil one as "queue metrics endpoint disabled".
+	router := server.NewRouter(locationHandler, jobCategoryHandler, jobTypeHandler, userHandler, emailHandler, nil, jobHandler, syncHandler, auditHandler, configHandler, authzHandler, replicationHandler, authServerHandler, cfg.Auth.JWTSecret)
 
 	// Use server address from config
 	serverAddr := cfg.ServerAddress()
@@ -133,3 +325,75 @@ func main() {
 		log.Fatalf("failed to run server: %v", err)
 	}
 }
+
+// buildAuthenticators constructs the ordered list of login backends selected
+// by AUTH_PROVIDERS, skipping any that are missing required configuration.
+func buildAuthenticators(cfg *config.Config, userRepo user.Repository) []user.Authenticator {
+	authenticators := make([]user.Authenticator, 0, len(cfg.Auth.ProviderList()))
+	for _, name := range cfg.Auth.ProviderList() {
+		switch name {
+		case "local":
+			authenticators = append(authenticators, user.NewLocalAuthenticator(userRepo))
+		case "ldap":
+			if cfg.Auth.LDAP.Addr == "" {
+				log.Printf("Warning: AUTH_PROVIDERS includes ldap but AUTH_LDAP_ADDR is not set; skipping")
+				continue
+			}
+			authenticators = append(authenticators, user.NewLDAPAuthenticator(
+				userRepo,
+				cfg.Auth.LDAP.Addr,
+				cfg.Auth.LDAP.BindDN,
+				cfg.Auth.LDAP.BindPass,
+				cfg.Auth.LDAP.UserBaseDN,
+				user.LDAPRoleMapping{},
+				cfg.Auth.LDAP.SelfRegistration,
+			))
+		case "jwt":
+			authenticators = append(authenticators, user.NewJWTAuthenticator(userRepo, cfg.Auth.JWTSecret))
+		default:
+			log.Printf("Warning: unknown auth provider %q in AUTH_PROVIDERS, ignoring", name)
+		}
+	}
+	return authenticators
+}
+
+// buildOAuthProviders translates the YAML-configured SSO providers into the
+// form user.Handler expects, skipping any that are missing required fields.
+func buildOAuthProviders(cfg *config.Config) []user.OAuthProviderConfig {
+	providers := make([]user.OAuthProviderConfig, 0, len(cfg.Auth.OAuthProviders))
+	for _, p := range cfg.Auth.OAuthProviders {
+		if p.Name == "" || p.ClientID == "" || p.ClientSecret == "" {
+			log.Printf("Warning: incomplete OAuth provider config %q, skipping", p.Name)
+			continue
+		}
+		providers = append(providers, user.OAuthProviderConfig{
+			Name:         p.Name,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			AuthURL:      p.AuthURL,
+			TokenURL:     p.TokenURL,
+			UserInfoURL:  p.UserInfoURL,
+			Scopes:       p.Scopes,
+			RedirectURL:  p.RedirectURL,
+		})
+	}
+	return providers
+}
+
+// buildAuthServerClients translates the YAML-configured /oauth/authorize
+// client registrations into the form authserver.InMemoryClientStore
+// expects, skipping any that are missing required fields.
+func buildAuthServerClients(cfg *config.Config) []authserver.RegisteredClient {
+	clients := make([]authserver.RegisteredClient, 0, len(cfg.Auth.AuthServerClients))
+	for _, c := range cfg.Auth.AuthServerClients {
+		if c.ClientID == "" || len(c.RedirectURIs) == 0 {
+			log.Printf("Warning: incomplete auth server client config %q, skipping", c.ClientID)
+			continue
+		}
+		clients = append(clients, authserver.RegisteredClient{
+			ClientID:     c.ClientID,
+			RedirectURIs: c.RedirectURIs,
+		})
+	}
+	return clients
+}
@@ -0,0 +1,13 @@
+package airtable
+
+import (
+	"time"
+
+	"lam-phuong-api/internal/ratelimit"
+)
+
+// Airtable enforces a hard 5 requests/sec/base limit. OutboundLimiter holds
+// that budget (5 calls per second under one shared key), and its Wait blocks
+// until a slot is free. No Client request method calls OutboundLimiter.Wait,
+// so outbound Airtable requests are not throttled by it.
+var OutboundLimiter = ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), "airtable-outbound", 5, time.Second)
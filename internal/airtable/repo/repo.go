@@ -0,0 +1,179 @@
+// Package repo provides a generic Airtable-backed CRUD implementation so
+// domain packages (jobtype, jobcategory, location, ...) don't each
+// reimplement FromAirtable/ToAirtableFields/List/Create/DeleteBySlug against
+// internal/airtable. A domain package describes its type once via a Schema
+// and gets a Repository[T] for free.
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lam-phuong-api/internal/airtable"
+)
+
+// bulkDeleteChunkSize mirrors Airtable's REST API limit of 10 records per
+// delete request (see airtable.ExampleUsage).
+const bulkDeleteChunkSize = 10
+
+// ErrNotFound is returned by Get and GetBySlug when no matching record exists.
+var ErrNotFound = errors.New("repo: record not found")
+
+// Schema describes how to map a domain type T to and from Airtable records.
+// Domain packages build exactly one Schema[T] value and pass it to New.
+type Schema[T any] struct {
+	// SlugField is the Airtable field name holding the slug used by
+	// GetBySlug/DeleteBySlug.
+	SlugField string
+
+	// FromRecord maps an Airtable record to a T.
+	FromRecord func(airtable.Record) (T, error)
+	// ToCreateFields converts a T to Airtable fields for record creation.
+	ToCreateFields func(T) map[string]interface{}
+	// ToUpdateFields converts a T to Airtable fields for a partial update.
+	ToUpdateFields func(T) map[string]interface{}
+	// SetID assigns an Airtable record ID to a T after creation.
+	SetID func(item *T, id string)
+}
+
+// Repository is a generic Airtable-backed CRUD implementation for a single
+// table, parameterized by the domain type T described by a Schema[T].
+type Repository[T any] struct {
+	client *airtable.Client
+	table  string
+	schema Schema[T]
+}
+
+// New creates a Repository[T] backed by client, operating on table according
+// to schema.
+func New[T any](client *airtable.Client, table string, schema Schema[T]) *Repository[T] {
+	return &Repository[T]{client: client, table: table, schema: schema}
+}
+
+// List returns every record in the table.
+func (r *Repository[T]) List(ctx context.Context) ([]T, error) {
+	records, err := r.client.ListRecords(ctx, r.table, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records from %s: %w", r.table, err)
+	}
+	return r.mapRecords(records)
+}
+
+// FilterByFormula returns every record matching the given Airtable formula,
+// e.g. fmt.Sprintf("{%s} = '%s'", FieldSlug, repo.EscapeFormulaValue(slug)).
+func (r *Repository[T]) FilterByFormula(ctx context.Context, formula string) ([]T, error) {
+	params := &airtable.ListParams{FilterByFormula: formula}
+	records, err := r.client.ListRecords(ctx, r.table, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", r.table, err)
+	}
+	return r.mapRecords(records)
+}
+
+// Get retrieves a single record by Airtable record ID.
+func (r *Repository[T]) Get(ctx context.Context, id string) (T, error) {
+	var zero T
+	record, err := r.client.GetRecord(ctx, r.table, id)
+	if err != nil {
+		return zero, fmt.Errorf("failed to get record %s from %s: %w", id, r.table, err)
+	}
+	return r.schema.FromRecord(record)
+}
+
+// GetBySlug retrieves the first record whose SlugField equals slug.
+func (r *Repository[T]) GetBySlug(ctx context.Context, slug string) (T, error) {
+	var zero T
+	matches, err := r.FilterByFormula(ctx, r.slugFilter(slug))
+	if err != nil {
+		return zero, err
+	}
+	if len(matches) == 0 {
+		return zero, ErrNotFound
+	}
+	return matches[0], nil
+}
+
+// Create saves item as a new record and returns it with its assigned ID.
+func (r *Repository[T]) Create(ctx context.Context, item T) (T, error) {
+	var zero T
+	fields := r.schema.ToCreateFields(item)
+	record, err := r.client.CreateRecord(ctx, r.table, fields)
+	if err != nil {
+		return zero, fmt.Errorf("failed to create record in %s: %w", r.table, err)
+	}
+	r.schema.SetID(&item, record.ID)
+	return item, nil
+}
+
+// Update partially updates the record with the given id and returns the
+// record as Airtable reports it back (picking up any server-side formulas).
+func (r *Repository[T]) Update(ctx context.Context, id string, item T) (T, error) {
+	var zero T
+	fields := r.schema.ToUpdateFields(item)
+	record, err := r.client.UpdateRecordPartial(ctx, r.table, id, fields)
+	if err != nil {
+		return zero, fmt.Errorf("failed to update record %s in %s: %w", id, r.table, err)
+	}
+	return r.schema.FromRecord(record)
+}
+
+// DeleteBySlug deletes every record whose SlugField equals slug, returning
+// the deleted record IDs.
+func (r *Repository[T]) DeleteBySlug(ctx context.Context, slug string) ([]string, error) {
+	params := &airtable.ListParams{FilterByFormula: r.slugFilter(slug)}
+	records, err := r.client.ListRecords(ctx, r.table, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s for slug %q: %w", r.table, slug, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	if err := r.BulkDelete(ctx, ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// BulkDelete deletes the given record IDs, chunking into groups of
+// bulkDeleteChunkSize to respect Airtable's per-request limit.
+func (r *Repository[T]) BulkDelete(ctx context.Context, ids []string) error {
+	for start := 0; start < len(ids); start += bulkDeleteChunkSize {
+		end := start + bulkDeleteChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := r.client.BulkDeleteRecords(ctx, r.table, ids[start:end]); err != nil {
+			return fmt.Errorf("failed to delete records from %s: %w", r.table, err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository[T]) slugFilter(slug string) string {
+	return fmt.Sprintf("{%s} = '%s'", r.schema.SlugField, EscapeFormulaValue(slug))
+}
+
+func (r *Repository[T]) mapRecords(records []airtable.Record) ([]T, error) {
+	items := make([]T, 0, len(records))
+	for _, record := range records {
+		item, err := r.schema.FromRecord(record)
+		if err != nil {
+			continue // best-effort: skip unmappable records, as the prior per-package repositories did
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// EscapeFormulaValue escapes single quotes so a user-controlled value can be
+// safely interpolated into an Airtable filterByFormula string literal.
+func EscapeFormulaValue(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"lam-phuong-api/internal/airtable"
+	"lam-phuong-api/internal/logger"
+)
+
+// Airtable field names for the dedicated audit_log table.
+const (
+	FieldActorUserID    = "ActorUserID"
+	FieldActorRole      = "ActorRole"
+	FieldAction         = "Action"
+	FieldResourceType   = "ResourceType"
+	FieldResourceID     = "ResourceID"
+	FieldChanges        = "Changes"
+	FieldRequestID      = "RequestID"
+	FieldSourceIP       = "SourceIP"
+	FieldEntryCreatedAt = "CreatedAt"
+)
+
+// AirtableRecorder implements Recorder, writing each entry to a dedicated
+// Airtable table (typically "audit_log"). Field/value diffs are marshaled
+// to JSON since Airtable has no native nested-list field type.
+type AirtableRecorder struct {
+	airtableClient *airtable.Client
+	airtableTable  string
+}
+
+// NewAirtableRecorder creates a recorder that writes to the given Airtable table.
+func NewAirtableRecorder(airtableClient *airtable.Client, airtableTable string) *AirtableRecorder {
+	return &AirtableRecorder{airtableClient: airtableClient, airtableTable: airtableTable}
+}
+
+func (r *AirtableRecorder) Record(ctx context.Context, entry Entry) error {
+	log := logger.FromContext(ctx)
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	changesJSON, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit changes: %w", err)
+	}
+
+	fields := map[string]interface{}{
+		FieldActorUserID:    entry.Actor.UserID,
+		FieldActorRole:      entry.Actor.Role,
+		FieldAction:         string(entry.Action),
+		FieldResourceType:   entry.ResourceType,
+		FieldResourceID:     entry.ResourceID,
+		FieldChanges:        string(changesJSON),
+		FieldRequestID:      entry.RequestID,
+		FieldSourceIP:       entry.SourceIP,
+		FieldEntryCreatedAt: entry.CreatedAt.Format(time.RFC3339),
+	}
+
+	if _, err := r.airtableClient.CreateRecord(ctx, r.airtableTable, fields); err != nil {
+		log.Error().Err(err).Str("resource_type", entry.ResourceType).Str("resource_id", entry.ResourceID).Msg("failed to write audit log entry to Airtable")
+		return fmt.Errorf("failed to write audit log entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AirtableRecorder) List(ctx context.Context, filter Filter) ([]Entry, error) {
+	params := &airtable.ListParams{
+		FilterByFormula: buildFilterFormula(filter),
+		Sort:            []airtable.SortParam{{Field: FieldEntryCreatedAt, Direction: "desc"}},
+	}
+
+	records, err := r.airtableClient.ListRecords(ctx, r.airtableTable, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+
+	log := logger.FromContext(ctx)
+	entries := make([]Entry, 0, len(records))
+	for _, record := range records {
+		entry, err := mapAirtableRecord(record)
+		if err != nil {
+			log.Warn().Err(err).Msg("skipping audit log record due to mapping error")
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return paginate(entries, filter), nil
+}
+
+func buildFilterFormula(filter Filter) string {
+	var clauses []string
+	if filter.ResourceType != "" {
+		clauses = append(clauses, fmt.Sprintf("{%s} = '%s'", FieldResourceType, escapeFormulaValue(filter.ResourceType)))
+	}
+	if filter.ResourceID != "" {
+		clauses = append(clauses, fmt.Sprintf("{%s} = '%s'", FieldResourceID, escapeFormulaValue(filter.ResourceID)))
+	}
+	if filter.Actor != "" {
+		clauses = append(clauses, fmt.Sprintf("{%s} = '%s'", FieldActorUserID, escapeFormulaValue(filter.Actor)))
+	}
+	if filter.Action != "" {
+		clauses = append(clauses, fmt.Sprintf("{%s} = '%s'", FieldAction, escapeFormulaValue(string(filter.Action))))
+	}
+	if !filter.From.IsZero() {
+		clauses = append(clauses, fmt.Sprintf("{%s} >= '%s'", FieldEntryCreatedAt, filter.From.Format(time.RFC3339)))
+	}
+	if !filter.To.IsZero() {
+		clauses = append(clauses, fmt.Sprintf("{%s} <= '%s'", FieldEntryCreatedAt, filter.To.Format(time.RFC3339)))
+	}
+
+	if len(clauses) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("AND(%s)", strings.Join(clauses, ", "))
+}
+
+func escapeFormulaValue(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+func mapAirtableRecord(record airtable.Record) (Entry, error) {
+	var changes []FieldChange
+	if raw, ok := record.Fields[FieldChanges].(string); ok && raw != "" {
+		if err := json.Unmarshal([]byte(raw), &changes); err != nil {
+			return Entry{}, fmt.Errorf("failed to unmarshal audit changes: %w", err)
+		}
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, getStringField(record.Fields, FieldEntryCreatedAt))
+
+	return Entry{
+		ID: record.ID,
+		Actor: Actor{
+			UserID: getStringField(record.Fields, FieldActorUserID),
+			Role:   getStringField(record.Fields, FieldActorRole),
+		},
+		Action:       Action(getStringField(record.Fields, FieldAction)),
+		ResourceType: getStringField(record.Fields, FieldResourceType),
+		ResourceID:   getStringField(record.Fields, FieldResourceID),
+		Changes:      changes,
+		RequestID:    getStringField(record.Fields, FieldRequestID),
+		SourceIP:     getStringField(record.Fields, FieldSourceIP),
+		CreatedAt:    createdAt,
+	}, nil
+}
+
+func getStringField(fields map[string]interface{}, key string) string {
+	if val, ok := fields[key]; ok {
+		if str, ok := val.(string); ok {
+			return str
+		}
+	}
+	return ""
+}
+
+var _ Recorder = (*AirtableRecorder)(nil)
@@ -0,0 +1,42 @@
+package audit
+
+import "context"
+
+type actorCtxKey struct{}
+type requestIDCtxKey struct{}
+
+// Actor identifies who performed an audited action.
+type Actor struct {
+	UserID string
+	Role   string
+}
+
+// WithActor returns a copy of ctx carrying the authenticated actor, set by
+// user.AuthMiddleware once a token has been validated.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// ActorFromContext returns the actor attached to ctx, or the zero Actor if
+// the request was unauthenticated.
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorCtxKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{}
+}
+
+// WithRequestID returns a copy of ctx carrying the request's correlation ID,
+// set by server.LoggerMiddleware.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID attached to ctx, or "" if none
+// was attached (e.g. in tests or background jobs).
+func RequestIDFromContext(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDCtxKey{}).(string); ok {
+		return requestID
+	}
+	return ""
+}
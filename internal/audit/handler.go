@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// Handler exposes HTTP handlers for querying the audit log.
+type Handler struct {
+	recorder Recorder
+}
+
+// NewHandler creates a handler backed by the given recorder.
+func NewHandler(recorder Recorder) *Handler {
+	return &Handler{recorder: recorder}
+}
+
+// Recorder returns the underlying Recorder so callers (e.g. main.go) can
+// wire it into repositories that emit diffs directly.
+func (h *Handler) Recorder() Recorder {
+	return h.recorder
+}
+
+// RegisterRoutes attaches audit routes to the supplied router group. Callers
+// are expected to mount this under a super-admin-only group.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/audit", h.ListAuditLog)
+}
+
+// ListAuditLog godoc
+// @Summary      List audit log entries
+// @Description  Get audit log entries, optionally filtered by resource, actor, verb, and date range (super admin only)
+// @Tags         audit
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        resource  query     string  false  "Resource type, e.g. location"
+// @Param        id        query     string  false  "Resource ID"
+// @Param        actor     query     string  false  "Actor user ID"
+// @Param        verb      query     string  false  "Action verb: create, update, delete, request"
+// @Param        from      query     string  false  "RFC3339 start of date range"
+// @Param        to        query     string  false  "RFC3339 end of date range"
+// @Param        limit     query     int     false  "Max entries to return"
+// @Param        offset    query     int     false  "Entries to skip"
+// @Success      200  {object}  audit.EntriesResponseWrapper  "Audit log entries retrieved successfully"
+// @Failure      400  {object}  response.ErrorResponse  "Validation error"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Router       /audit [get]
+func (h *Handler) ListAuditLog(c *gin.Context) {
+	filter := Filter{
+		ResourceType: c.Query("resource"),
+		ResourceID:   c.Query("id"),
+		Actor:        c.Query("actor"),
+		Action:       Action(c.Query("verb")),
+		Limit:        20,
+	}
+
+	if from := c.Query("from"); from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			response.ValidationError(c, "Invalid 'from' date, expected RFC3339", nil)
+			return
+		}
+		filter.From = parsed
+	}
+
+	if to := c.Query("to"); to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			response.ValidationError(c, "Invalid 'to' date, expected RFC3339", nil)
+			return
+		}
+		filter.To = parsed
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 0 {
+			response.ValidationError(c, "Invalid 'limit', expected a non-negative integer", nil)
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	if offset := c.Query("offset"); offset != "" {
+		parsed, err := strconv.Atoi(offset)
+		if err != nil || parsed < 0 {
+			response.ValidationError(c, "Invalid 'offset', expected a non-negative integer", nil)
+			return
+		}
+		filter.Offset = parsed
+	}
+
+	entries, err := h.recorder.List(c.Request.Context(), filter)
+	if err != nil {
+		response.InternalError(c, "Failed to list audit log entries: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, entries, "Audit log entries retrieved successfully")
+}
+
+// EntriesResponseWrapper wraps a slice of Entry in the standard API response format for Swagger
+// @Description Response containing a list of audit log entries
+type EntriesResponseWrapper struct {
+	Success bool    `json:"success" example:"true"`
+	Data    []Entry `json:"data"`
+	Message string  `json:"message" example:"Audit log entries retrieved successfully"`
+}
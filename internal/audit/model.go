@@ -0,0 +1,52 @@
+package audit
+
+import "time"
+
+// Action is the verb recorded for an audited event.
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRequest Action = "request" // generic non-GET HTTP request with no repository-level diff
+)
+
+// FieldChange describes a single field's before/after values, used to build
+// Update entries' Changes slice.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// Entry is a single audit log record: who did what to which resource, and
+// (for mutations the repository layer understands) exactly which fields
+// changed.
+type Entry struct {
+	ID           string        `json:"id,omitempty"`
+	Actor        Actor         `json:"actor"`
+	Action       Action        `json:"action"`
+	ResourceType string        `json:"resource_type"`
+	ResourceID   string        `json:"resource_id"`
+	Changes      []FieldChange `json:"changes,omitempty"`
+	RequestID    string        `json:"request_id"`
+	SourceIP     string        `json:"source_ip"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// DiffFields compares a resource's field map before and after a mutation
+// (as produced by ToAirtableFieldsForUpdate) and returns only the entries
+// whose values actually changed. Repositories call this from Update to
+// build an Entry's Changes without hand-rolling the comparison.
+func DiffFields(before, after map[string]interface{}) []FieldChange {
+	changes := make([]FieldChange, 0, len(after))
+	for field, newValue := range after {
+		oldValue := before[field]
+		if oldValue == newValue {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+	}
+	return changes
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Filter narrows a List query. Zero-value fields are treated as "no filter".
+type Filter struct {
+	ResourceType string
+	ResourceID   string
+	Actor        string
+	Action       Action
+	From         time.Time
+	To           time.Time
+	Limit        int
+	Offset       int
+}
+
+// Recorder persists audit entries and answers filtered queries over them.
+type Recorder interface {
+	Record(ctx context.Context, entry Entry) error
+	List(ctx context.Context, filter Filter) ([]Entry, error)
+}
+
+// InMemoryRecorder is a Recorder backed by a slice guarded by a mutex.
+// Entries are kept in process memory only; cmd/server wires the
+// AirtableRecorder, not this one.
+type InMemoryRecorder struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// NewInMemoryRecorder creates an empty in-memory audit recorder.
+func NewInMemoryRecorder() *InMemoryRecorder {
+	return &InMemoryRecorder{}
+}
+
+func (r *InMemoryRecorder) Record(ctx context.Context, entry Entry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *InMemoryRecorder) List(ctx context.Context, filter Filter) ([]Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]Entry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		if matches(entry, filter) {
+			matched = append(matched, entry)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+
+	return paginate(matched, filter), nil
+}
+
+func matches(entry Entry, filter Filter) bool {
+	if filter.ResourceType != "" && entry.ResourceType != filter.ResourceType {
+		return false
+	}
+	if filter.ResourceID != "" && entry.ResourceID != filter.ResourceID {
+		return false
+	}
+	if filter.Actor != "" && entry.Actor.UserID != filter.Actor {
+		return false
+	}
+	if filter.Action != "" && entry.Action != filter.Action {
+		return false
+	}
+	if !filter.From.IsZero() && entry.CreatedAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && entry.CreatedAt.After(filter.To) {
+		return false
+	}
+	return true
+}
+
+func paginate(entries []Entry, filter Filter) []Entry {
+	offset := filter.Offset
+	if offset < 0 || offset > len(entries) {
+		offset = len(entries)
+	}
+	entries = entries[offset:]
+
+	if filter.Limit > 0 && filter.Limit < len(entries) {
+		entries = entries[:filter.Limit]
+	}
+	return entries
+}
+
+var _ Recorder = (*InMemoryRecorder)(nil)
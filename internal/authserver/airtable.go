@@ -0,0 +1,109 @@
+package authserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lam-phuong-api/internal/airtable"
+)
+
+// Airtable field names for the dedicated oauth_requests table.
+const (
+	FieldCode        = "Code"
+	FieldClientID    = "ClientID"
+	FieldRedirectURI = "RedirectURI"
+	FieldScope       = "Scope"
+	FieldState       = "State"
+	FieldUserID      = "UserID"
+	FieldCreatedAt   = "CreatedAt"
+	FieldExpiresAt   = "ExpiresAt"
+)
+
+// AirtableAuthRequestStore implements AuthRequestStore against a dedicated
+// Airtable table. Unlike InMemoryAuthRequestStore, a code saved by one API
+// instance can be redeemed by another, which matters once the authorization
+// server runs behind a load balancer with more than one instance.
+type AirtableAuthRequestStore struct {
+	airtableClient *airtable.Client
+	airtableTable  string
+}
+
+// NewAirtableAuthRequestStore creates a store backed by the given Airtable table.
+func NewAirtableAuthRequestStore(airtableClient *airtable.Client, airtableTable string) *AirtableAuthRequestStore {
+	return &AirtableAuthRequestStore{airtableClient: airtableClient, airtableTable: airtableTable}
+}
+
+// Save writes req as a new Airtable record.
+func (s *AirtableAuthRequestStore) Save(ctx context.Context, req AuthorizationRequest) error {
+	fields := map[string]interface{}{
+		FieldCode:        req.Code,
+		FieldClientID:    req.ClientID,
+		FieldRedirectURI: req.RedirectURI,
+		FieldScope:       req.Scope,
+		FieldState:       req.State,
+		FieldUserID:      req.UserID,
+		FieldCreatedAt:   req.CreatedAt.Format(time.RFC3339),
+		FieldExpiresAt:   req.ExpiresAt.Format(time.RFC3339),
+	}
+
+	if _, err := s.airtableClient.CreateRecord(ctx, s.airtableTable, fields); err != nil {
+		return fmt.Errorf("failed to save authorization request: %w", err)
+	}
+	return nil
+}
+
+// Consume looks up the record for code and deletes it so it can't be
+// redeemed again.
+func (s *AirtableAuthRequestStore) Consume(ctx context.Context, code string) (AuthorizationRequest, error) {
+	formula := fmt.Sprintf("{%s} = '%s'", FieldCode, escapeFormulaValue(code))
+	records, err := s.airtableClient.ListRecords(ctx, s.airtableTable, &airtable.ListParams{FilterByFormula: formula})
+	if err != nil {
+		return AuthorizationRequest{}, fmt.Errorf("failed to look up authorization code: %w", err)
+	}
+	if len(records) == 0 {
+		return AuthorizationRequest{}, fmt.Errorf("unknown or already-redeemed authorization code")
+	}
+
+	record := records[0]
+	if err := s.airtableClient.DeleteRecord(ctx, s.airtableTable, record.ID); err != nil {
+		return AuthorizationRequest{}, fmt.Errorf("failed to redeem authorization code: %w", err)
+	}
+
+	req := mapAirtableRecord(record)
+	if req.Expired() {
+		return AuthorizationRequest{}, fmt.Errorf("authorization code has expired")
+	}
+	return req, nil
+}
+
+func mapAirtableRecord(record airtable.Record) AuthorizationRequest {
+	createdAt, _ := time.Parse(time.RFC3339, getStringField(record.Fields, FieldCreatedAt))
+	expiresAt, _ := time.Parse(time.RFC3339, getStringField(record.Fields, FieldExpiresAt))
+	return AuthorizationRequest{
+		Code:        getStringField(record.Fields, FieldCode),
+		ClientID:    getStringField(record.Fields, FieldClientID),
+		RedirectURI: getStringField(record.Fields, FieldRedirectURI),
+		Scope:       getStringField(record.Fields, FieldScope),
+		State:       getStringField(record.Fields, FieldState),
+		UserID:      getStringField(record.Fields, FieldUserID),
+		CreatedAt:   createdAt,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+func getStringField(fields map[string]interface{}, key string) string {
+	if val, ok := fields[key]; ok {
+		if str, ok := val.(string); ok {
+			return str
+		}
+	}
+	return ""
+}
+
+func escapeFormulaValue(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+var _ AuthRequestStore = (*AirtableAuthRequestStore)(nil)
@@ -0,0 +1,310 @@
+package authserver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/user"
+)
+
+// codeTTL is how long an authorization code issued by Authorize remains
+// redeemable at /oauth/token, mirroring the short-lived-nonce convention
+// the relying-party flow in internal/user/oauth.go already uses for state.
+const codeTTL = 2 * time.Minute
+
+// Handler exposes a standards-shaped OAuth2/OIDC authorization server:
+// /oauth/authorize, /oauth/token, /oauth/userinfo, the OpenID discovery
+// document, and a JWKS endpoint. It delegates resource-owner
+// authentication to a pluggable AuthProvider so the same endpoints serve
+// either local password accounts or a federated identity provider.
+type Handler struct {
+	provider    AuthProvider
+	store       AuthRequestStore
+	clients     ClientStore
+	keySet      *KeySet
+	issuer      string
+	tokenExpiry time.Duration
+}
+
+// NewHandler creates an authorization server handler. issuer is the
+// externally-reachable base URL advertised in the discovery document and
+// embedded as the "iss" claim of issued tokens. clients is consulted by
+// Authorize to reject any client_id/redirect_uri pair that wasn't
+// pre-registered.
+func NewHandler(provider AuthProvider, store AuthRequestStore, clients ClientStore, keySet *KeySet, issuer string, tokenExpiry time.Duration) *Handler {
+	return &Handler{provider: provider, store: store, clients: clients, keySet: keySet, issuer: issuer, tokenExpiry: tokenExpiry}
+}
+
+// KeySet returns the handler's signing key set, so callers (main.go) can
+// wire the same verifier into user.RegisterJWKSVerifier that Token signs
+// tokens with.
+func (h *Handler) KeySet() *KeySet {
+	return h.keySet
+}
+
+// RegisterRoutes mounts the authorization server's endpoints at their
+// standard, well-known paths on the bare engine rather than under /api:
+// OIDC discovery and JWKS URLs are conventionally served from the
+// application root.
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/.well-known/openid-configuration", h.OpenIDConfiguration)
+	router.GET("/oauth/jwks.json", h.JWKS)
+
+	oauth := router.Group("/oauth")
+	{
+		oauth.GET("/authorize", h.Authorize)
+		oauth.POST("/authorize", h.Authorize)
+		oauth.POST("/token", h.Token)
+		oauth.GET("/userinfo", h.UserInfo)
+	}
+}
+
+// OpenIDConfiguration serves the OpenID Connect discovery document.
+// @Summary Get the OpenID Connect discovery document
+// @Tags oauth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /.well-known/openid-configuration [get]
+func (h *Handler) OpenIDConfiguration(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                                h.issuer,
+		"authorization_endpoint":                h.issuer + "/oauth/authorize",
+		"token_endpoint":                        h.issuer + "/oauth/token",
+		"userinfo_endpoint":                     h.issuer + "/oauth/userinfo",
+		"jwks_uri":                              h.issuer + "/oauth/jwks.json",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"token_endpoint_auth_methods_supported": []string{"none"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+// JWKS serves the public key set used to verify RS256-signed tokens.
+// @Summary Get the JSON Web Key Set
+// @Tags oauth
+// @Produce json
+// @Success 200 {object} JWKSDocument
+// @Router /oauth/jwks.json [get]
+func (h *Handler) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.keySet.JWKS())
+}
+
+// authorizePayload carries resource-owner credentials for Authorize. A real
+// deployment would front this with a login page; accepting credentials
+// directly here keeps the authorization_code grant usable without one, the
+// same tradeoff LoginRequest already makes for the legacy /auth/login flow.
+type authorizePayload struct {
+	Username string `form:"username"`
+	Password string `form:"password"`
+	IDToken  string `form:"id_token"`
+}
+
+// Authorize resolves the resource owner via the configured AuthProvider and
+// redirects to redirect_uri with a single-use authorization code.
+// @Summary Start an authorization_code grant
+// @Tags oauth
+// @Param client_id query string true "Client identifier"
+// @Param redirect_uri query string true "Where to send the issued code"
+// @Param response_type query string true "Must be 'code'"
+// @Param scope query string false "Requested scope"
+// @Param state query string false "Opaque value echoed back to the client"
+// @Success 302
+// @Failure 400 {object} response.Response
+// @Router /oauth/authorize [get]
+func (h *Handler) Authorize(c *gin.Context) {
+	if c.Query("response_type") != "code" {
+		response.BadRequest(c, "Only response_type=code is supported", nil)
+		return
+	}
+	redirectURI := c.Query("redirect_uri")
+	if redirectURI == "" {
+		response.BadRequest(c, "redirect_uri is required", nil)
+		return
+	}
+
+	clientID := c.Query("client_id")
+	client, ok := h.clients.Lookup(c.Request.Context(), clientID)
+	if !ok || !client.AllowsRedirectURI(redirectURI) {
+		// Unregistered client_id or unregistered redirect_uri: respond
+		// directly rather than redirecting, since the redirect_uri itself
+		// is what's unverified here (RFC 6749 §10.6). Authenticating the
+		// resource owner first would let an attacker collect a valid code
+		// at a URI of their choosing.
+		response.BadRequest(c, "Unknown client_id or unregistered redirect_uri", nil)
+		return
+	}
+
+	var payload authorizePayload
+	if err := c.ShouldBind(&payload); err != nil {
+		response.BadRequest(c, "Invalid request data", nil)
+		return
+	}
+
+	resourceOwner, err := h.provider.Authenticate(c.Request.Context(), Credentials{
+		Username: payload.Username,
+		Password: payload.Password,
+		IDToken:  payload.IDToken,
+	})
+	if err != nil {
+		response.InvalidAuth(c, "Invalid credentials")
+		return
+	}
+
+	code, err := newCode()
+	if err != nil {
+		response.InternalError(c, "Failed to start authorization")
+		return
+	}
+
+	now := time.Now()
+	req := AuthorizationRequest{
+		Code:        code,
+		ClientID:    c.Query("client_id"),
+		RedirectURI: redirectURI,
+		Scope:       c.Query("scope"),
+		State:       c.Query("state"),
+		UserID:      resourceOwner.ID,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(codeTTL),
+	}
+	if err := h.store.Save(c.Request.Context(), req); err != nil {
+		response.InternalError(c, "Failed to start authorization")
+		return
+	}
+
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		response.BadRequest(c, "Invalid redirect_uri", nil)
+		return
+	}
+	query := redirect.Query()
+	query.Set("code", code)
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirect.RawQuery = query.Encode()
+
+	c.Redirect(http.StatusFound, redirect.String())
+}
+
+// tokenPayload is the POST /oauth/token request body
+// (application/x-www-form-urlencoded, per RFC 6749).
+type tokenPayload struct {
+	GrantType   string `form:"grant_type" binding:"required"`
+	Code        string `form:"code"`
+	RedirectURI string `form:"redirect_uri"`
+}
+
+// tokenResponse is the POST /oauth/token success body, per RFC 6749/OIDC
+// core. It is returned as-is, not wrapped in response.Response, so
+// off-the-shelf OAuth2/OIDC client libraries can parse it.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Token redeems a single-use authorization code for an access token and ID
+// token, both RS256-signed by this server's KeySet.
+// @Summary Exchange an authorization code for tokens
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200 {object} tokenResponse
+// @Failure 400 {object} response.Response
+// @Router /oauth/token [post]
+func (h *Handler) Token(c *gin.Context) {
+	var payload tokenPayload
+	if err := c.ShouldBind(&payload); err != nil {
+		response.BadRequest(c, "Invalid request data", nil)
+		return
+	}
+	if payload.GrantType != "authorization_code" {
+		response.BadRequest(c, "Only grant_type=authorization_code is supported", nil)
+		return
+	}
+
+	req, err := h.store.Consume(c.Request.Context(), payload.Code)
+	if err != nil {
+		response.InvalidAuth(c, "Invalid or expired authorization code")
+		return
+	}
+	if payload.RedirectURI != "" && payload.RedirectURI != req.RedirectURI {
+		response.InvalidAuth(c, "redirect_uri does not match the authorization request")
+		return
+	}
+
+	now := time.Now()
+	claims := &user.Claims{
+		UserID: req.UserID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    h.issuer,
+			Subject:   req.UserID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(h.tokenExpiry)),
+		},
+	}
+	accessToken, err := h.keySet.Sign(claims)
+	if err != nil {
+		response.InternalError(c, "Failed to issue access token")
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenResponse{
+		AccessToken: accessToken,
+		IDToken:     accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(h.tokenExpiry.Seconds()),
+	})
+}
+
+// UserInfo returns the claims of the Bearer token presented, per the OIDC
+// UserInfo endpoint contract. It accepts the same RS256 tokens AuthMiddleware
+// does, so a client that completed the authorization_code grant here can
+// call it straight away.
+// @Summary Get the authenticated subject's claims
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} response.Response
+// @Router /oauth/userinfo [get]
+func (h *Handler) UserInfo(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	const prefix = "Bearer "
+	if len(authHeader) <= len(prefix) || authHeader[:len(prefix)] != prefix {
+		response.Unauthorized(c, "Authorization header required")
+		return
+	}
+
+	claims, err := h.keySet.Verify(authHeader[len(prefix):])
+	if err != nil {
+		response.InvalidToken(c, "Invalid or expired token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sub":   claims.Subject,
+		"email": claims.Email,
+		"role":  claims.Role,
+	})
+}
+
+// newCode generates an opaque, URL-safe authorization code.
+func newCode() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,99 @@
+package authserver
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"lam-phuong-api/internal/user"
+)
+
+// KeySet holds the RSA key pair the authorization server uses to sign
+// RS256 tokens and to publish its JWKS document. A fresh key pair is
+// generated per process start, so RS256 tokens signed before a restart
+// aren't honored after one (unlike HS256 tokens, whose jwtSecret comes from
+// config and survives a restart).
+type KeySet struct {
+	kid        string
+	privateKey *rsa.PrivateKey
+}
+
+// NewKeySet generates a new RSA-2048 key pair with a random key ID.
+func NewKeySet() (*KeySet, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate auth server signing key: %w", err)
+	}
+
+	kidBytes := make([]byte, 8)
+	if _, err := rand.Read(kidBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate key id: %w", err)
+	}
+
+	return &KeySet{kid: hex.EncodeToString(kidBytes), privateKey: key}, nil
+}
+
+// Sign issues an RS256-signed JWT for claims, tagging it with this key
+// set's kid so Verify and AuthMiddleware know which public key to check it
+// against.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = k.kid
+	return token.SignedString(k.privateKey)
+}
+
+// Verify validates an RS256 token signed by this key set and returns its
+// claims in the same shape as user.ValidateToken, so AuthMiddleware can
+// treat RS256 and HS256 tokens identically once verified.
+func (k *KeySet) Verify(tokenString string) (*user.Claims, error) {
+	claims := &user.Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		if token.Header["kid"] != k.kid {
+			return nil, fmt.Errorf("unknown key id %v", token.Header["kid"])
+		}
+		return &k.privateKey.PublicKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return claims, nil
+}
+
+// JWK is one entry of a JSON Web Key Set, per RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSDocument is the JSON Web Key Set document published at /oauth/jwks.json.
+type JWKSDocument struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public half of the key set in JWKS form.
+func (k *KeySet) JWKS() JWKSDocument {
+	pub := k.privateKey.PublicKey
+	return JWKSDocument{Keys: []JWK{{
+		Kty: "RSA",
+		Use: "sig",
+		Kid: k.kid,
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}}
+}
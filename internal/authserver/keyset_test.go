@@ -0,0 +1,80 @@
+package authserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"lam-phuong-api/internal/user"
+)
+
+func testClaims() *user.Claims {
+	return &user.Claims{
+		UserID: "user-1",
+		Role:   user.RoleUser,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func TestKeySetSignVerifyRoundTrip(t *testing.T) {
+	keySet, err := NewKeySet()
+	if err != nil {
+		t.Fatalf("NewKeySet returned an error: %v", err)
+	}
+
+	signed, err := keySet.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Sign returned an error: %v", err)
+	}
+
+	claims, err := keySet.Verify(signed)
+	if err != nil {
+		t.Fatalf("Verify returned an error for a token this key set signed: %v", err)
+	}
+	if claims.Subject != "user-1" {
+		t.Errorf("claims.Subject = %q, want %q", claims.Subject, "user-1")
+	}
+}
+
+func TestKeySetVerifyRejectsTokenFromAnotherKeySet(t *testing.T) {
+	keySet, err := NewKeySet()
+	if err != nil {
+		t.Fatalf("NewKeySet returned an error: %v", err)
+	}
+	other, err := NewKeySet()
+	if err != nil {
+		t.Fatalf("NewKeySet returned an error: %v", err)
+	}
+
+	signed, err := other.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Sign returned an error: %v", err)
+	}
+
+	if _, err := keySet.Verify(signed); err == nil {
+		t.Error("Verify should reject a token signed by a different key set (mismatched kid)")
+	}
+}
+
+func TestKeySetJWKSPublishesItsOwnKid(t *testing.T) {
+	keySet, err := NewKeySet()
+	if err != nil {
+		t.Fatalf("NewKeySet returned an error: %v", err)
+	}
+
+	jwks := keySet.JWKS()
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("expected exactly one key in the JWKS document, got %d", len(jwks.Keys))
+	}
+	if jwks.Keys[0].Kid != keySet.kid {
+		t.Errorf("JWKS kid = %q, want %q", jwks.Keys[0].Kid, keySet.kid)
+	}
+	if jwks.Keys[0].Alg != "RS256" {
+		t.Errorf("JWKS alg = %q, want RS256", jwks.Keys[0].Alg)
+	}
+}
@@ -0,0 +1,28 @@
+// Package authserver exposes a standards-shaped OAuth2/OIDC authorization
+// server (authorization_code grant, JWKS, OpenID discovery) alongside the
+// existing password-based /auth/login in internal/user, so operators can
+// point external clients at /oauth/* without giving up the legacy flow.
+package authserver
+
+import "time"
+
+// AuthorizationRequest is the short-lived server-side state for one
+// authorization_code grant, bridging GET /oauth/authorize (where the
+// resource owner is identified) and POST /oauth/token (where the code is
+// redeemed). Codes are single-use; ExpiresAt lets stores reject a stale
+// one without a separate cleanup job.
+type AuthorizationRequest struct {
+	Code        string
+	ClientID    string
+	RedirectURI string
+	Scope       string
+	State       string
+	UserID      string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Expired reports whether the authorization code is too old to redeem.
+func (r AuthorizationRequest) Expired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
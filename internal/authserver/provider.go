@@ -0,0 +1,102 @@
+package authserver
+
+import (
+	"context"
+	"fmt"
+
+	"lam-phuong-api/internal/types"
+	"lam-phuong-api/internal/user"
+)
+
+// Credentials carries whichever form of proof-of-identity an AuthProvider
+// needs: a username/password pair for LocalPasswordProvider, or an
+// externally-issued ID token for OIDCProvider.
+type Credentials struct {
+	Username string
+	Password string
+	IDToken  string
+}
+
+// AuthProvider resolves Credentials to a local User, letting operators flip
+// between local password auth and a federated OIDC provider via config
+// without Handler needing to know which one it's talking to.
+type AuthProvider interface {
+	Name() string
+	Authenticate(ctx context.Context, creds Credentials) (user.User, error)
+}
+
+// LocalPasswordProvider wraps the existing email/password login behavior,
+// so the authorization server can issue its own codes/tokens for the same
+// accounts the legacy /auth/login endpoint serves.
+type LocalPasswordProvider struct {
+	repo user.Repository
+}
+
+// NewLocalPasswordProvider creates a provider backed by repo.
+func NewLocalPasswordProvider(repo user.Repository) *LocalPasswordProvider {
+	return &LocalPasswordProvider{repo: repo}
+}
+
+// Name identifies this provider in logs and config.
+func (p *LocalPasswordProvider) Name() string { return "local" }
+
+// Authenticate verifies creds.Username/Password against repo.
+func (p *LocalPasswordProvider) Authenticate(ctx context.Context, creds Credentials) (user.User, error) {
+	return user.NewLocalAuthenticator(p.repo).Login(ctx, creds.Username, creds.Password)
+}
+
+// OIDCVerifier verifies an ID token issued by an external provider and
+// returns the email it attests to. Kept as an interface, separate from any
+// one provider's client library, so OIDCProvider doesn't have to know
+// whether it's talking to Google, GitHub, or something internal.
+type OIDCVerifier interface {
+	Verify(ctx context.Context, idToken string) (email string, err error)
+}
+
+// OIDCProvider authenticates by verifying an externally-issued ID token
+// rather than a password, then resolving it to a local user the same way
+// the relying-party flow in internal/user/oauth.go links an external
+// identity to an account.
+type OIDCProvider struct {
+	verifier OIDCVerifier
+	repo     user.Repository
+}
+
+// NewOIDCProvider creates a provider that verifies federated ID tokens with
+// verifier and resolves them against repo.
+func NewOIDCProvider(verifier OIDCVerifier, repo user.Repository) *OIDCProvider {
+	return &OIDCProvider{verifier: verifier, repo: repo}
+}
+
+// Name identifies this provider in logs and config.
+func (p *OIDCProvider) Name() string { return "oidc" }
+
+// Authenticate verifies creds.IDToken and looks up the user it attests to.
+// Like LocalPasswordProvider, it never creates an account: linking a new
+// federated identity stays the job of the existing OAuthCallback flow.
+func (p *OIDCProvider) Authenticate(ctx context.Context, creds Credentials) (user.User, error) {
+	if creds.IDToken == "" {
+		return user.User{}, fmt.Errorf("oidc provider requires an id_token")
+	}
+
+	rawEmail, err := p.verifier.Verify(ctx, creds.IDToken)
+	if err != nil {
+		return user.User{}, fmt.Errorf("failed to verify id token: %w", err)
+	}
+
+	email, err := types.NewEmail(rawEmail)
+	if err != nil {
+		return user.User{}, fmt.Errorf("oidc provider returned an invalid email address: %w", err)
+	}
+
+	u, ok := p.repo.GetByEmail(email)
+	if !ok {
+		return user.User{}, fmt.Errorf("no account linked to %s", email)
+	}
+	return u, nil
+}
+
+var (
+	_ AuthProvider = (*LocalPasswordProvider)(nil)
+	_ AuthProvider = (*OIDCProvider)(nil)
+)
@@ -0,0 +1,108 @@
+package authserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// AuthRequestStore persists in-flight authorization_code grants between
+// /oauth/authorize and /oauth/token. Consume both fetches and deletes in
+// one step so a code can't be redeemed twice.
+type AuthRequestStore interface {
+	Save(ctx context.Context, req AuthorizationRequest) error
+	Consume(ctx context.Context, code string) (AuthorizationRequest, error)
+}
+
+// InMemoryAuthRequestStore stores authorization requests in memory.
+// Authorization codes are short-lived, single-instance state, so unlike
+// User/Location there is no requirement to survive a restart.
+type InMemoryAuthRequestStore struct {
+	mu   sync.Mutex
+	data map[string]AuthorizationRequest
+}
+
+// NewInMemoryAuthRequestStore creates an empty in-memory authorization
+// request store.
+func NewInMemoryAuthRequestStore() *InMemoryAuthRequestStore {
+	return &InMemoryAuthRequestStore{data: make(map[string]AuthorizationRequest)}
+}
+
+// Save stores req, keyed by its code.
+func (s *InMemoryAuthRequestStore) Save(ctx context.Context, req AuthorizationRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[req.Code] = req
+	return nil
+}
+
+// Consume looks up and deletes the request for code in a single step.
+func (s *InMemoryAuthRequestStore) Consume(ctx context.Context, code string) (AuthorizationRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, ok := s.data[code]
+	if !ok {
+		return AuthorizationRequest{}, fmt.Errorf("unknown or already-redeemed authorization code")
+	}
+	delete(s.data, code)
+
+	if req.Expired() {
+		return AuthorizationRequest{}, fmt.Errorf("authorization code has expired")
+	}
+	return req, nil
+}
+
+var _ AuthRequestStore = (*InMemoryAuthRequestStore)(nil)
+
+// RegisteredClient is a client_id pre-registered with this authorization
+// server, together with the redirect_uri values a code may be issued to
+// for it. Authorize rejects any client_id/redirect_uri pair that isn't
+// registered here before authenticating the resource owner, per RFC 6749
+// §10.6 (open redirect / authorization code exfiltration).
+type RegisteredClient struct {
+	ClientID     string
+	RedirectURIs []string
+}
+
+// AllowsRedirectURI reports whether redirectURI is one of the client's
+// registered URIs.
+func (c RegisteredClient) AllowsRedirectURI(redirectURI string) bool {
+	for _, uri := range c.RedirectURIs {
+		if uri == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientStore resolves a client_id to its registered client, if any.
+type ClientStore interface {
+	Lookup(ctx context.Context, clientID string) (RegisteredClient, bool)
+}
+
+// InMemoryClientStore serves a fixed set of registered clients, loaded
+// once from config at startup. Unlike AuthRequestStore, there is nothing
+// to write at runtime, so it has no Save/mutation method.
+type InMemoryClientStore struct {
+	clients map[string]RegisteredClient
+}
+
+// NewInMemoryClientStore creates a client store from a fixed list of
+// registered clients.
+func NewInMemoryClientStore(clients []RegisteredClient) *InMemoryClientStore {
+	byID := make(map[string]RegisteredClient, len(clients))
+	for _, client := range clients {
+		byID[client.ClientID] = client
+	}
+	return &InMemoryClientStore{clients: byID}
+}
+
+// Lookup returns the registered client for clientID, if any.
+func (s *InMemoryClientStore) Lookup(ctx context.Context, clientID string) (RegisteredClient, bool) {
+	client, ok := s.clients[clientID]
+	return client, ok
+}
+
+var _ ClientStore = (*InMemoryClientStore)(nil)
@@ -0,0 +1,82 @@
+package authserver
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemoryAuthRequestStoreConsumeIsSingleUse(t *testing.T) {
+	store := NewInMemoryAuthRequestStore()
+	ctx := context.Background()
+
+	req := AuthorizationRequest{
+		Code:      "abc123",
+		ClientID:  "client-1",
+		UserID:    "user-1",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	if err := store.Save(ctx, req); err != nil {
+		t.Fatalf("Save returned an error: %v", err)
+	}
+
+	consumed, err := store.Consume(ctx, "abc123")
+	if err != nil {
+		t.Fatalf("Consume returned an error: %v", err)
+	}
+	if consumed.UserID != "user-1" {
+		t.Errorf("consumed.UserID = %q, want %q", consumed.UserID, "user-1")
+	}
+
+	if _, err := store.Consume(ctx, "abc123"); err == nil {
+		t.Error("Consume should reject redeeming the same code twice")
+	}
+}
+
+func TestInMemoryAuthRequestStoreConsumeRejectsExpired(t *testing.T) {
+	store := NewInMemoryAuthRequestStore()
+	ctx := context.Background()
+
+	req := AuthorizationRequest{
+		Code:      "expired-code",
+		UserID:    "user-1",
+		CreatedAt: time.Now().Add(-time.Hour),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	if err := store.Save(ctx, req); err != nil {
+		t.Fatalf("Save returned an error: %v", err)
+	}
+
+	if _, err := store.Consume(ctx, "expired-code"); err == nil {
+		t.Error("Consume should reject an expired authorization code")
+	}
+}
+
+func TestInMemoryAuthRequestStoreConsumeRejectsUnknownCode(t *testing.T) {
+	store := NewInMemoryAuthRequestStore()
+	if _, err := store.Consume(context.Background(), "never-issued"); err == nil {
+		t.Error("Consume should reject a code that was never saved")
+	}
+}
+
+func TestInMemoryClientStoreLookup(t *testing.T) {
+	store := NewInMemoryClientStore([]RegisteredClient{
+		{ClientID: "dashboard", RedirectURIs: []string{"https://dashboard.example.com/callback"}},
+	})
+
+	client, ok := store.Lookup(context.Background(), "dashboard")
+	if !ok {
+		t.Fatal("Lookup should find a registered client_id")
+	}
+	if !client.AllowsRedirectURI("https://dashboard.example.com/callback") {
+		t.Error("AllowsRedirectURI should allow a registered redirect_uri")
+	}
+	if client.AllowsRedirectURI("https://attacker.example.com/callback") {
+		t.Error("AllowsRedirectURI should reject an unregistered redirect_uri")
+	}
+
+	if _, ok := store.Lookup(context.Background(), "unknown-client"); ok {
+		t.Error("Lookup should not find an unregistered client_id")
+	}
+}
@@ -0,0 +1,39 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// Handler exposes the effective RBAC permission matrix for inspection.
+// Like config.Handler, it applies no role check of its own: the caller
+// mounts it under an already admin-gated route group.
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a handler backed by store.
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes attaches the roles inspection route to the supplied router group.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/roles", h.ListRoles)
+}
+
+// ListRoles godoc
+// @Summary      Inspect the effective RBAC permission matrix
+// @Description  List every (role, resource, action) policy currently in effect (admin only)
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "Policy matrix retrieved successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Router       /admin/roles [get]
+func (h *Handler) ListRoles(c *gin.Context) {
+	response.Success(c, http.StatusOK, h.store.Table().Policies(), "Policy matrix retrieved successfully")
+}
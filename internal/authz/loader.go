@@ -0,0 +1,42 @@
+package authz
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// PolicyLoader produces a policy Table from some external source.
+type PolicyLoader interface {
+	Load() (*Table, error)
+}
+
+// YAMLFileLoader loads a policy Table from a YAML file shaped like:
+//
+//	policies:
+//	  - role: Admin
+//	    resource: job_type
+//	    action: create
+type YAMLFileLoader struct {
+	Path string
+}
+
+type policyFile struct {
+	Policies []Policy `mapstructure:"policies"`
+}
+
+// Load reads and parses the YAML file at l.Path.
+func (l YAMLFileLoader) Load() (*Table, error) {
+	v := viper.New()
+	v.SetConfigFile(l.Path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read policy file %s: %w", l.Path, err)
+	}
+
+	var file policyFile
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", l.Path, err)
+	}
+
+	return NewTable(file.Policies), nil
+}
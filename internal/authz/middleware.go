@@ -0,0 +1,37 @@
+package authz
+
+import (
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// RequirePermission creates a middleware that allows the request only if the
+// authenticated user's role is granted action on resource by store's policy
+// table. It reads the role from the "user_role" context key stamped by
+// user.AuthMiddleware, the same key user.RequireRole uses, so route groups
+// can mix the two gating styles during migration.
+func RequirePermission(store *Store, resource Resource, action Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, exists := c.Get("user_role")
+		if !exists {
+			response.Forbidden(c, "User role not found in context")
+			c.Abort()
+			return
+		}
+
+		roleStr, ok := userRole.(string)
+		if !ok {
+			response.Forbidden(c, "Invalid user role type")
+			c.Abort()
+			return
+		}
+
+		if !store.Table().Allows(Role(roleStr), resource, action) {
+			response.Forbidden(c, "Insufficient permissions for this action")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,114 @@
+// Package authz implements hierarchical, per-resource RBAC on top of the
+// flat role strings already stamped into the Gin context by
+// user.AuthMiddleware. It replaces ad hoc role-string comparisons with a
+// (role, resource, action) -> allow policy table that can be inspected and
+// reloaded at runtime.
+package authz
+
+import "lam-phuong-api/internal/user"
+
+// Role identifies a principal's place in the hierarchy. Values line up with
+// the flat role strings user.RoleSuperAdmin/RoleAdmin/RoleUser already in
+// use, so the policy table can be driven straight off the "user_role"
+// context key.
+type Role string
+
+// Resource identifies a kind of thing a Policy can grant access to.
+type Resource string
+
+// Known resources.
+const (
+	ResourceJobType  Resource = "job_type"
+	ResourceLocation Resource = "location"
+	ResourceUser     Resource = "user"
+	ResourceConfig   Resource = "config"
+)
+
+// Action identifies what a Role is allowed to do to a Resource.
+type Action string
+
+// Known actions.
+const (
+	ActionRead   Action = "read"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionAdmin  Action = "admin"
+)
+
+// Policy grants Role the ability to perform Action on Resource.
+type Policy struct {
+	Role     Role     `mapstructure:"role" json:"role"`
+	Resource Resource `mapstructure:"resource" json:"resource"`
+	Action   Action   `mapstructure:"action" json:"action"`
+}
+
+// Table is an allow-list of Policies, queried by Allows.
+type Table struct {
+	policies []Policy
+}
+
+// NewTable builds a Table from an explicit policy list, e.g. one loaded by
+// a PolicyLoader.
+func NewTable(policies []Policy) *Table {
+	return &Table{policies: append([]Policy(nil), policies...)}
+}
+
+// Allows reports whether role may perform action on resource. A nil Table
+// denies everything, so a Store that failed to load never fails open.
+func (t *Table) Allows(role Role, resource Resource, action Action) bool {
+	if t == nil {
+		return false
+	}
+	for _, p := range t.policies {
+		if p.Role == role && p.Resource == resource && p.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Policies returns the table's entries, for presenting the effective
+// permission matrix via Handler.ListRoles.
+func (t *Table) Policies() []Policy {
+	if t == nil {
+		return nil
+	}
+	return append([]Policy(nil), t.policies...)
+}
+
+// DefaultPolicies returns the built-in policy table, mirroring today's
+// coarse-grained behavior: Super Admin has full access everywhere, Admin has
+// full access except updating other users (still Super Admin-only, as
+// enforced in server.NewRouter), and User can read/create/delete job types
+// and locations but has no access to user management or runtime config.
+func DefaultPolicies() *Table {
+	var policies []Policy
+
+	for _, resource := range []Resource{ResourceJobType, ResourceLocation} {
+		for _, role := range []Role{Role(user.RoleSuperAdmin), Role(user.RoleAdmin), Role(user.RoleUser)} {
+			policies = append(policies,
+				Policy{Role: role, Resource: resource, Action: ActionRead},
+				Policy{Role: role, Resource: resource, Action: ActionCreate},
+				Policy{Role: role, Resource: resource, Action: ActionDelete},
+			)
+		}
+	}
+
+	policies = append(policies,
+		Policy{Role: Role(user.RoleAdmin), Resource: ResourceUser, Action: ActionRead},
+		Policy{Role: Role(user.RoleAdmin), Resource: ResourceUser, Action: ActionCreate},
+		Policy{Role: Role(user.RoleAdmin), Resource: ResourceUser, Action: ActionDelete},
+		Policy{Role: Role(user.RoleAdmin), Resource: ResourceConfig, Action: ActionRead},
+		Policy{Role: Role(user.RoleAdmin), Resource: ResourceConfig, Action: ActionUpdate},
+	)
+
+	for _, action := range []Action{ActionRead, ActionCreate, ActionUpdate, ActionDelete, ActionAdmin} {
+		policies = append(policies,
+			Policy{Role: Role(user.RoleSuperAdmin), Resource: ResourceUser, Action: action},
+			Policy{Role: Role(user.RoleSuperAdmin), Resource: ResourceConfig, Action: action},
+		)
+	}
+
+	return NewTable(policies)
+}
@@ -0,0 +1,52 @@
+package authz
+
+import "testing"
+
+func TestTableAllows(t *testing.T) {
+	table := NewTable([]Policy{
+		{Role: "Admin", Resource: ResourceUser, Action: ActionRead},
+	})
+
+	if !table.Allows("Admin", ResourceUser, ActionRead) {
+		t.Error("Allows should grant a policy explicitly in the table")
+	}
+	if table.Allows("Admin", ResourceUser, ActionDelete) {
+		t.Error("Allows should deny an action not in the table")
+	}
+	if table.Allows("User", ResourceUser, ActionRead) {
+		t.Error("Allows should deny a role not in the table")
+	}
+}
+
+func TestNilTableDeniesEverything(t *testing.T) {
+	var table *Table
+	if table.Allows("Admin", ResourceUser, ActionRead) {
+		t.Error("a nil Table should deny every request rather than panicking or failing open")
+	}
+	if table.Policies() != nil {
+		t.Error("a nil Table should report no policies")
+	}
+}
+
+func TestDefaultPoliciesMirrorsCoarseGrainedBehavior(t *testing.T) {
+	table := DefaultPolicies()
+
+	cases := []struct {
+		role     Role
+		resource Resource
+		action   Action
+		want     bool
+	}{
+		{Role("User"), ResourceJobType, ActionCreate, true},
+		{Role("User"), ResourceUser, ActionRead, false},
+		{Role("Admin"), ResourceUser, ActionRead, true},
+		{Role("Admin"), ResourceUser, ActionUpdate, false}, // super-admin only, enforced in server.NewRouter
+		{Role("Super Admin"), ResourceUser, ActionUpdate, true},
+		{Role("Super Admin"), ResourceConfig, ActionAdmin, true},
+	}
+	for _, c := range cases {
+		if got := table.Allows(c.role, c.resource, c.action); got != c.want {
+			t.Errorf("Allows(%q, %q, %q) = %v, want %v", c.role, c.resource, c.action, got, c.want)
+		}
+	}
+}
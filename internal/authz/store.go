@@ -0,0 +1,47 @@
+package authz
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+// Store holds the live policy Table, reloadable at runtime without
+// restarting the server.
+type Store struct {
+	loader  PolicyLoader
+	current atomic.Pointer[Table]
+}
+
+// NewStore creates a Store seeded with fallback and, if loader is non-nil,
+// immediately attempts to load from it. A failed initial load logs a
+// warning and keeps fallback in effect rather than denying every request.
+func NewStore(loader PolicyLoader, fallback *Table) *Store {
+	s := &Store{loader: loader}
+	s.current.Store(fallback)
+	if loader != nil {
+		if err := s.Reload(); err != nil {
+			log.Printf("Warning: failed to load RBAC policy file, using defaults: %v", err)
+		}
+	}
+	return s
+}
+
+// Table returns the currently active policy table.
+func (s *Store) Table() *Table {
+	return s.current.Load()
+}
+
+// Reload re-reads the policy source and swaps it in atomically. Safe to
+// call from an admin endpoint or a file-watcher; in-flight requests keep
+// using whichever table they already loaded.
+func (s *Store) Reload() error {
+	if s.loader == nil {
+		return nil
+	}
+	table, err := s.loader.Load()
+	if err != nil {
+		return err
+	}
+	s.current.Store(table)
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"os"
 	"strings"
 
 	"lam-phuong-api/internal/airtable"
@@ -17,6 +18,17 @@ type Config struct {
 	Airtable AirtableConfig `mapstructure:"airtable"`
 	Auth     AuthConfig     `mapstructure:"auth"`
 	Email    EmailConfig    `mapstructure:"email"`
+	Jobs     JobsConfig     `mapstructure:"jobs"`
+}
+
+// JobsConfig holds settings for the async job/worker subsystem (internal/job).
+type JobsConfig struct {
+	// MaxWorkers is the number of goroutines draining the job queue.
+	MaxWorkers int `mapstructure:"max_workers"`
+	// DefaultAsync makes mutating endpoints that support it run as
+	// background jobs by default. A request can still override this with
+	// ?async=true/false, or opt out with "Prefer: respond-async=false".
+	DefaultAsync bool `mapstructure:"default_async"`
 }
 
 // ServerConfig holds server-related configuration
@@ -25,6 +37,9 @@ type ServerConfig struct {
 	Host         string `mapstructure:"host"`
 	ReadTimeout  int    `mapstructure:"read_timeout"`
 	WriteTimeout int    `mapstructure:"write_timeout"`
+	// LogLevel is one of zerolog's level names (debug, info, warn, error);
+	// see logger.SetLevel. Editable at runtime via PUT /admin/config.
+	LogLevel string `mapstructure:"log_level"`
 }
 
 // AirtableConfig holds Airtable-related configuration
@@ -33,12 +48,101 @@ type AirtableConfig struct {
 	BaseID             string `mapstructure:"base_id"`
 	LocationsTableName string `mapstructure:"locations_table_name"`
 	UsersTableName     string `mapstructure:"users_table_name"`
+	AuditLogTableName  string `mapstructure:"audit_log_table_name"`
 }
 
 // AuthConfig holds authentication-related configuration
 type AuthConfig struct {
 	JWTSecret   string `mapstructure:"jwt_secret"`
 	TokenExpiry int    `mapstructure:"token_expiry"` // in hours
+	// Providers is a comma-separated list of enabled login backends, e.g.
+	// "local,ldap". Defaults to "local" when unset.
+	Providers string `mapstructure:"providers"`
+	LDAP      LDAPConfig
+	// OAuthProviders configures SSO login backends (Google, GitHub, generic
+	// OIDC), each reachable at /api/auth/oauth/:name/login. Set via a YAML
+	// config file (viper env vars can't express a list of structs cleanly),
+	// e.g.:
+	//   auth:
+	//     oauth_providers:
+	//       - name: google
+	//         client_id: "..."
+	//         client_secret: "..."
+	//         auth_url: "https://accounts.google.com/o/oauth2/v2/auth"
+	//         token_url: "https://oauth2.googleapis.com/token"
+	//         userinfo_url: "https://openidconnect.googleapis.com/v1/userinfo"
+	//         scopes: [openid, email, profile]
+	OAuthProviders []OAuthProviderConfig `mapstructure:"oauth_providers"`
+	// AuthServerClients registers the client_id/redirect_uri pairs the
+	// /oauth/authorize endpoint (internal/authserver) will issue a code to.
+	// A client_id absent here, or a redirect_uri not in its list, is
+	// rejected before the resource owner is asked to authenticate. Set via
+	// a YAML config file, e.g.:
+	//   auth:
+	//     auth_server_clients:
+	//       - client_id: "internal-dashboard"
+	//         redirect_uris: ["https://dashboard.example.com/callback"]
+	AuthServerClients []AuthServerClientConfig `mapstructure:"auth_server_clients"`
+	// Argon2 tunes the cost of new password hashes (existing hashes keep
+	// their own embedded parameters regardless of these settings).
+	Argon2 Argon2Config `mapstructure:"argon2"`
+}
+
+// Argon2Config holds the argon2id cost parameters used for new password
+// hashes. See user.Argon2Params for field meanings.
+type Argon2Config struct {
+	MemoryKB    uint32 `mapstructure:"memory_kb"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+}
+
+// OAuthProviderConfig describes one OAuth2/OIDC SSO provider.
+type OAuthProviderConfig struct {
+	Name         string   `mapstructure:"name"` // path segment, e.g. "google"
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	AuthURL      string   `mapstructure:"auth_url"`
+	TokenURL     string   `mapstructure:"token_url"`
+	UserInfoURL  string   `mapstructure:"userinfo_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+}
+
+// AuthServerClientConfig registers one client_id allowed to start an
+// authorization_code grant at /oauth/authorize, and the redirect_uri
+// values a code may be issued to for it.
+type AuthServerClientConfig struct {
+	ClientID     string   `mapstructure:"client_id"`
+	RedirectURIs []string `mapstructure:"redirect_uris"`
+}
+
+// LDAPConfig holds settings for the optional LDAP authentication backend.
+type LDAPConfig struct {
+	Addr       string `mapstructure:"addr"`         // e.g. ldaps://ldap.example.com:636
+	BindDN     string `mapstructure:"bind_dn"`      // service account DN used for the search bind
+	BindPass   string `mapstructure:"bind_pass"`    // service account password
+	UserBaseDN string `mapstructure:"user_base_dn"` // subtree to search for users
+	// SelfRegistration controls whether a successful LDAP bind may create a
+	// new local user record. When false, LDAPAuthenticator.Login rejects
+	// anyone who doesn't already have an Airtable user row, so directory
+	// access alone can't grant an account.
+	SelfRegistration bool `mapstructure:"self_registration"`
+}
+
+// ProviderList splits Providers on commas, trims whitespace, and defaults
+// to ["local"] when unset.
+func (a AuthConfig) ProviderList() []string {
+	if strings.TrimSpace(a.Providers) == "" {
+		return []string{"local"}
+	}
+	parts := strings.Split(a.Providers, ",")
+	providers := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			providers = append(providers, p)
+		}
+	}
+	return providers
 }
 
 // EmailConfig holds email-related configuration
@@ -54,8 +158,8 @@ type EmailConfig struct {
 }
 
 var (
-	// Global config instance
-	globalConfig *Config
+	// globalStore backs Get/Subscribe/AdminStore. Set once by Load.
+	globalStore *Store
 )
 
 // Load initializes and loads configuration from environment variables and .env file
@@ -79,21 +183,47 @@ func Load() (*Config, error) {
 	// Set default values
 	setDefaults()
 
-	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	var envConfig Config
+	if err := viper.Unmarshal(&envConfig); err != nil {
 		return nil, fmt.Errorf("unable to decode config into struct: %w", err)
 	}
 
-	globalConfig = &config
-	return &config, nil
+	overridesPath := strings.TrimSpace(os.Getenv(overridesPathEnvVar))
+	if overridesPath == "" {
+		overridesPath = defaultOverridesPath
+	}
+
+	globalStore = newStore(envConfig, overridesPath)
+	return globalStore.Get(), nil
 }
 
-// Get returns the global configuration instance
+// Get returns the live configuration instance (post any admin overrides).
 func Get() *Config {
-	if globalConfig == nil {
+	if globalStore == nil {
 		log.Fatal("Config not loaded. Call config.Load() first.")
 	}
-	return globalConfig
+	return globalStore.Get()
+}
+
+// Subscribe registers fn to run (with the new Config) every time an admin
+// applies or resets config via the /admin/config API, so a consumer can pick
+// up the change without a process restart. Only consumers that subscribe
+// see a change; anything built from Config at startup keeps its original
+// values. See Store.Subscribe.
+func Subscribe(fn func(*Config)) {
+	if globalStore == nil {
+		log.Fatal("Config not loaded. Call config.Load() first.")
+	}
+	globalStore.Subscribe(fn)
+}
+
+// AdminStore returns the Store backing Get/Subscribe, for wiring into
+// config.NewHandler. Load must be called first.
+func AdminStore() *Store {
+	if globalStore == nil {
+		log.Fatal("Config not loaded. Call config.Load() first.")
+	}
+	return globalStore
 }
 
 // setDefaults sets default values for configuration
@@ -103,16 +233,27 @@ func setDefaults() {
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.read_timeout", 15)
 	viper.SetDefault("server.write_timeout", 15)
+	viper.SetDefault("server.log_level", "info")
 
 	// Airtable defaults (empty - should be set via env vars)
 	viper.SetDefault("airtable.api_key", "")
 	viper.SetDefault("airtable.base_id", "")
 	viper.SetDefault("airtable.locations_table_name", "Địa điểm")
 	viper.SetDefault("airtable.users_table_name", "Người dùng")
+	viper.SetDefault("airtable.audit_log_table_name", "audit_log")
 
 	// Auth defaults
 	viper.SetDefault("auth.jwt_secret", "")
 	viper.SetDefault("auth.token_expiry", 24) // 24 hours
+	viper.SetDefault("auth.providers", "local")
+	viper.SetDefault("auth.ldap.addr", "")
+	viper.SetDefault("auth.ldap.bind_dn", "")
+	viper.SetDefault("auth.ldap.bind_pass", "")
+	viper.SetDefault("auth.ldap.user_base_dn", "")
+	viper.SetDefault("auth.ldap.self_registration", true)
+	viper.SetDefault("auth.argon2.memory_kb", 65536) // 64 MiB
+	viper.SetDefault("auth.argon2.iterations", 3)
+	viper.SetDefault("auth.argon2.parallelism", 2)
 
 	// Email defaults
 	viper.SetDefault("email.smtp_host", "")
@@ -123,6 +264,10 @@ func setDefaults() {
 	viper.SetDefault("email.from_name", "Lam Phuong")
 	viper.SetDefault("email.base_url", "http://localhost:8080")
 	viper.SetDefault("email.use_tls", true)
+
+	// Jobs defaults
+	viper.SetDefault("jobs.max_workers", 4)
+	viper.SetDefault("jobs.default_async", false)
 }
 
 // Validate checks if required configuration values are set
@@ -131,6 +276,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("server port is required")
 	}
 
+	if c.Server.LogLevel == "" {
+		c.Server.LogLevel = "info"
+	}
+
 	if c.Airtable.APIKey == "" {
 		return fmt.Errorf("airtable API key is required (set AIRTABLE_API_KEY)")
 	}
@@ -149,6 +298,11 @@ func (c *Config) Validate() error {
 		c.Airtable.UsersTableName = "Người dùng" // Fallback to default if somehow empty
 	}
 
+	// AuditLogTableName has a default value, so it's optional but we ensure it's set
+	if c.Airtable.AuditLogTableName == "" {
+		c.Airtable.AuditLogTableName = "audit_log" // Fallback to default if somehow empty
+	}
+
 	// Validate auth config
 	if c.Auth.JWTSecret == "" {
 		return fmt.Errorf("JWT secret is required (set AUTH_JWT_SECRET)")
@@ -158,6 +312,36 @@ func (c *Config) Validate() error {
 		c.Auth.TokenExpiry = 24 // Default to 24 hours
 	}
 
+	if c.Auth.Argon2.MemoryKB == 0 {
+		c.Auth.Argon2.MemoryKB = 65536
+	}
+	if c.Auth.Argon2.Iterations == 0 {
+		c.Auth.Argon2.Iterations = 3
+	}
+	if c.Auth.Argon2.Parallelism == 0 {
+		c.Auth.Argon2.Parallelism = 2
+	}
+
+	if c.Jobs.MaxWorkers <= 0 {
+		c.Jobs.MaxWorkers = 4
+	}
+
+	// Validate the LDAP backend's config only if it's actually selected, so
+	// deployments that never set AUTH_PROVIDERS=...,ldap aren't forced to
+	// fill these in.
+	for _, provider := range c.Auth.ProviderList() {
+		if provider != "ldap" {
+			continue
+		}
+		if c.Auth.LDAP.Addr == "" {
+			return fmt.Errorf("AUTH_LDAP_ADDR is required when ldap is listed in AUTH_PROVIDERS")
+		}
+		if c.Auth.LDAP.UserBaseDN == "" {
+			return fmt.Errorf("AUTH_LDAP_USER_BASE_DN is required when ldap is listed in AUTH_PROVIDERS")
+		}
+		break
+	}
+
 	return nil
 }
 
@@ -170,3 +354,31 @@ func (c *Config) ServerAddress() string {
 func (c *Config) NewAirtableClient() (*airtable.Client, error) {
 	return airtable.NewClient(c.Airtable.APIKey, c.Airtable.BaseID)
 }
+
+// Redacted returns a copy of c with secret fields (Airtable API key, JWT
+// secret, LDAP bind password, OAuth client secrets, SMTP password) replaced
+// by a placeholder, safe to return from GET /admin/config.
+func (c Config) Redacted() Config {
+	c.Airtable.APIKey = redactSecret(c.Airtable.APIKey)
+	c.Auth.JWTSecret = redactSecret(c.Auth.JWTSecret)
+	c.Auth.LDAP.BindPass = redactSecret(c.Auth.LDAP.BindPass)
+	c.Email.SMTPPassword = redactSecret(c.Email.SMTPPassword)
+
+	providers := make([]OAuthProviderConfig, len(c.Auth.OAuthProviders))
+	copy(providers, c.Auth.OAuthProviders)
+	for i := range providers {
+		providers[i].ClientSecret = redactSecret(providers[i].ClientSecret)
+	}
+	c.Auth.OAuthProviders = providers
+
+	return c
+}
+
+// redactSecret replaces a non-empty secret with a fixed placeholder so the
+// caller can still tell "unset" from "set" without leaking the value.
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "***REDACTED***"
+}
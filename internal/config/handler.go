@@ -0,0 +1,95 @@
+package config
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// Handler exposes an admin API for inspecting and hot-reloading runtime
+// configuration. Callers should guard RegisterRoutes' group with
+// user.RequireAdmin() (this package can't import user directly: user
+// doesn't depend on config, and neither should depend on the other).
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a handler backed by the given Store.
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// RegisterRoutes attaches config routes to the supplied router group.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/config", h.GetConfig)
+	router.PUT("/admin/config", h.UpdateConfig)
+	router.POST("/admin/config/reset", h.ResetConfig)
+}
+
+// GetConfig godoc
+// @Summary      Get the live configuration
+// @Description  Get the current config with secrets (Airtable API key, JWT secret, LDAP/OAuth/SMTP credentials) redacted (requires admin role)
+// @Tags         config
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "Config retrieved successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Router       /admin/config [get]
+func (h *Handler) GetConfig(c *gin.Context) {
+	response.Success(c, http.StatusOK, h.store.Get().Redacted(), "Config retrieved successfully")
+}
+
+// UpdateConfig godoc
+// @Summary      Update the live configuration
+// @Description  Apply changes to a whitelisted subset of config (email settings, table names, token expiry, log level) without a restart (requires admin role)
+// @Tags         config
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        update  body      AdminConfigUpdate  true  "Config update"
+// @Success      200     {object}  response.Response  "Config updated successfully"
+// @Failure      400     {object}  response.ErrorResponse  "Validation error"
+// @Failure      401     {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  response.ErrorResponse  "Forbidden"
+// @Failure      500     {object}  response.ErrorResponse  "Failed to persist overrides"
+// @Router       /admin/config [put]
+func (h *Handler) UpdateConfig(c *gin.Context) {
+	var update AdminConfigUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	updated, err := h.store.Apply(update)
+	if err != nil {
+		response.InternalError(c, "Failed to persist config overrides: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, updated.Redacted(), "Config updated successfully")
+}
+
+// ResetConfig godoc
+// @Summary      Reset configuration overrides
+// @Description  Discard admin overrides and restore config from environment variables (requires admin role)
+// @Tags         config
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "Config reset successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Failure      500  {object}  response.ErrorResponse  "Failed to remove override file"
+// @Router       /admin/config/reset [post]
+func (h *Handler) ResetConfig(c *gin.Context) {
+	reset, err := h.store.Reset()
+	if err != nil {
+		response.InternalError(c, "Failed to reset config overrides: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, reset.Redacted(), "Config reset to environment values")
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// overridesPathEnvVar names the environment variable pointing at the JSON
+// file used to persist admin-applied config overrides across restarts.
+const overridesPathEnvVar = "CONFIG_OVERRIDES_PATH"
+
+// defaultOverridesPath is used when overridesPathEnvVar is unset.
+const defaultOverridesPath = "config_overrides.json"
+
+// AdminConfigUpdate is the whitelisted subset of Config fields editable via
+// PUT /admin/config: email settings, table names, token expiry, and log
+// level. A zero value means "leave unchanged", matching the partial-update
+// convention used elsewhere in this codebase (see user's UpdateUser payload).
+type AdminConfigUpdate struct {
+	SMTPHost           string `json:"smtp_host"`
+	SMTPPort           string `json:"smtp_port"`
+	SMTPUsername       string `json:"smtp_username"`
+	SMTPPassword       string `json:"smtp_password"`
+	FromEmail          string `json:"from_email"`
+	FromName           string `json:"from_name"`
+	BaseURL            string `json:"base_url"`
+	LocationsTableName string `json:"locations_table_name"`
+	UsersTableName     string `json:"users_table_name"`
+	AuditLogTableName  string `json:"audit_log_table_name"`
+	TokenExpiry        int    `json:"token_expiry"`
+	LogLevel           string `json:"log_level"`
+}
+
+// applyAdminUpdate writes each non-zero field of update onto cfg.
+func applyAdminUpdate(cfg *Config, update AdminConfigUpdate) {
+	if update.SMTPHost != "" {
+		cfg.Email.SMTPHost = update.SMTPHost
+	}
+	if update.SMTPPort != "" {
+		cfg.Email.SMTPPort = update.SMTPPort
+	}
+	if update.SMTPUsername != "" {
+		cfg.Email.SMTPUsername = update.SMTPUsername
+	}
+	if update.SMTPPassword != "" {
+		cfg.Email.SMTPPassword = update.SMTPPassword
+	}
+	if update.FromEmail != "" {
+		cfg.Email.FromEmail = update.FromEmail
+	}
+	if update.FromName != "" {
+		cfg.Email.FromName = update.FromName
+	}
+	if update.BaseURL != "" {
+		cfg.Email.BaseURL = update.BaseURL
+	}
+	if update.LocationsTableName != "" {
+		cfg.Airtable.LocationsTableName = update.LocationsTableName
+	}
+	if update.UsersTableName != "" {
+		cfg.Airtable.UsersTableName = update.UsersTableName
+	}
+	if update.AuditLogTableName != "" {
+		cfg.Airtable.AuditLogTableName = update.AuditLogTableName
+	}
+	if update.TokenExpiry > 0 {
+		cfg.Auth.TokenExpiry = update.TokenExpiry
+	}
+	if update.LogLevel != "" {
+		cfg.Server.LogLevel = update.LogLevel
+	}
+}
+
+// mergeAdminUpdate copies each non-zero field of src onto dst, so repeated
+// PUTs accumulate rather than clobbering earlier overrides.
+func mergeAdminUpdate(dst *AdminConfigUpdate, src AdminConfigUpdate) {
+	if src.SMTPHost != "" {
+		dst.SMTPHost = src.SMTPHost
+	}
+	if src.SMTPPort != "" {
+		dst.SMTPPort = src.SMTPPort
+	}
+	if src.SMTPUsername != "" {
+		dst.SMTPUsername = src.SMTPUsername
+	}
+	if src.SMTPPassword != "" {
+		dst.SMTPPassword = src.SMTPPassword
+	}
+	if src.FromEmail != "" {
+		dst.FromEmail = src.FromEmail
+	}
+	if src.FromName != "" {
+		dst.FromName = src.FromName
+	}
+	if src.BaseURL != "" {
+		dst.BaseURL = src.BaseURL
+	}
+	if src.LocationsTableName != "" {
+		dst.LocationsTableName = src.LocationsTableName
+	}
+	if src.UsersTableName != "" {
+		dst.UsersTableName = src.UsersTableName
+	}
+	if src.AuditLogTableName != "" {
+		dst.AuditLogTableName = src.AuditLogTableName
+	}
+	if src.TokenExpiry > 0 {
+		dst.TokenExpiry = src.TokenExpiry
+	}
+	if src.LogLevel != "" {
+		dst.LogLevel = src.LogLevel
+	}
+}
+
+// Store holds the live Config behind an atomic.Pointer so readers (Get)
+// never block on writers, and notifies Subscribe callbacks whenever an
+// admin applies or resets config via the /admin/config API.
+type Store struct {
+	envConfig     Config // as loaded purely from the environment; Reset restores this
+	overridesPath string
+
+	mu        sync.Mutex
+	overrides AdminConfigUpdate // cumulative admin overrides, persisted to overridesPath
+
+	current atomic.Pointer[Config]
+
+	subMu       sync.Mutex
+	subscribers []func(*Config)
+}
+
+// newStore builds a Store from envConfig, applying any overrides persisted
+// at overridesPath from a previous process.
+func newStore(envConfig Config, overridesPath string) *Store {
+	s := &Store{envConfig: envConfig, overridesPath: overridesPath}
+
+	if persisted, err := readOverrides(overridesPath); err == nil {
+		s.overrides = persisted
+	}
+
+	merged := envConfig
+	applyAdminUpdate(&merged, s.overrides)
+	s.current.Store(&merged)
+	return s
+}
+
+// Get returns the live Config. Safe for concurrent use; never blocks.
+func (s *Store) Get() *Config {
+	return s.current.Load()
+}
+
+// Subscribe registers fn to be called with the new Config every time Apply
+// or Reset changes the live config.
+func (s *Store) Subscribe(fn func(*Config)) {
+	s.subMu.Lock()
+	s.subscribers = append(s.subscribers, fn)
+	s.subMu.Unlock()
+}
+
+// Apply merges update onto the cumulative admin overrides, persists them to
+// overridesPath, recomputes the live Config from envConfig, and notifies
+// subscribers.
+func (s *Store) Apply(update AdminConfigUpdate) (*Config, error) {
+	s.mu.Lock()
+	mergeAdminUpdate(&s.overrides, update)
+	cumulative := s.overrides
+	s.mu.Unlock()
+
+	if err := writeOverrides(s.overridesPath, cumulative); err != nil {
+		return nil, err
+	}
+
+	merged := s.envConfig
+	applyAdminUpdate(&merged, cumulative)
+	s.publish(&merged)
+	return &merged, nil
+}
+
+// Reset discards all admin overrides, deletes the on-disk override file,
+// restores the config loaded from the environment, and notifies subscribers.
+func (s *Store) Reset() (*Config, error) {
+	s.mu.Lock()
+	s.overrides = AdminConfigUpdate{}
+	s.mu.Unlock()
+
+	if err := os.Remove(s.overridesPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	envCopy := s.envConfig
+	s.publish(&envCopy)
+	return &envCopy, nil
+}
+
+// publish stores cfg as the live config and notifies every subscriber.
+func (s *Store) publish(cfg *Config) {
+	s.current.Store(cfg)
+
+	s.subMu.Lock()
+	subs := append([]func(*Config){}, s.subscribers...)
+	s.subMu.Unlock()
+
+	for _, fn := range subs {
+		fn(cfg)
+	}
+}
+
+// readOverrides loads a previously persisted AdminConfigUpdate from path.
+func readOverrides(path string) (AdminConfigUpdate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AdminConfigUpdate{}, err
+	}
+	var update AdminConfigUpdate
+	if err := json.Unmarshal(data, &update); err != nil {
+		return AdminConfigUpdate{}, err
+	}
+	return update, nil
+}
+
+// writeOverrides persists update to path as indented JSON.
+func writeOverrides(path string, update AdminConfigUpdate) error {
+	data, err := json.MarshalIndent(update, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
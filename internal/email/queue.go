@@ -0,0 +1,331 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"lam-phuong-api/internal/types"
+)
+
+// Queue retry tuning: a job is moved to the dead-letter state after
+// queueMaxAttempts have all failed, each retry backing off by
+// queueBaseDelay*2^(attempt-1) (capped at queueMaxDelay), less a random
+// jitter of up to half that delay, so many simultaneously-failing jobs don't
+// all retry in lockstep.
+const (
+	queueMaxAttempts  = 5
+	queueBaseDelay    = 2 * time.Second
+	queueMaxDelay     = 5 * time.Minute
+	queuePollInterval = 250 * time.Millisecond
+)
+
+// QueueStatus represents a QueuedEmail's lifecycle state.
+type QueueStatus string
+
+// QueuedEmail lifecycle states.
+const (
+	QueueStatusPending  QueueStatus = "PENDING"
+	QueueStatusInFlight QueueStatus = "IN_FLIGHT"
+	QueueStatusSent     QueueStatus = "SENT"
+	QueueStatusDead     QueueStatus = "DEAD"
+)
+
+// QueuedEmail is one SendTemplate job waiting to be delivered.
+type QueuedEmail struct {
+	ID            string
+	Template      string
+	ToEmail       types.Email
+	Data          map[string]interface{}
+	Status        QueueStatus
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	NextAttemptAt time.Time
+}
+
+// QueueMetrics summarizes a QueueStore's current state for the admin
+// /admin/email/queue endpoint.
+type QueueMetrics struct {
+	Pending  int `json:"pending"`
+	InFlight int `json:"in_flight"`
+	Retried  int `json:"retried"`
+	Dead     int `json:"dead"`
+}
+
+// QueueStore holds queued emails, including ones permanently moved to the
+// dead-letter state. Implementations must be safe for concurrent use.
+// InMemoryQueueStore is the only implementation, and it keeps queue state in
+// process memory only: queued and dead-lettered emails do not survive a
+// process restart.
+type QueueStore interface {
+	// Enqueue persists a new PENDING item.
+	Enqueue(item QueuedEmail) error
+	// ClaimNext atomically picks one PENDING item whose NextAttemptAt has
+	// elapsed, marks it IN_FLIGHT, and returns it. Returns ok=false if none
+	// are ready.
+	ClaimNext(now time.Time) (item QueuedEmail, ok bool, err error)
+	// MarkSent marks id as successfully delivered.
+	MarkSent(id string) error
+	// MarkRetry returns id to PENDING, due again at nextAttemptAt, recording
+	// lastErr and incrementing the lifetime retry counter Metrics reports.
+	MarkRetry(id string, nextAttemptAt time.Time, lastErr string) error
+	// MarkDead moves id to the dead-letter state after it has exhausted its
+	// retries, recording lastErr.
+	MarkDead(id string, lastErr string) error
+	// Metrics reports pending/in-flight/retried/dead counts.
+	Metrics() QueueMetrics
+}
+
+// InMemoryQueueStore is a QueueStore backed by a map, safe for concurrent
+// use. Queue state does not survive a process restart.
+type InMemoryQueueStore struct {
+	mu           sync.Mutex
+	items        map[string]QueuedEmail
+	retriedTotal int
+}
+
+// NewInMemoryQueueStore creates an empty in-memory queue store.
+func NewInMemoryQueueStore() *InMemoryQueueStore {
+	return &InMemoryQueueStore{items: make(map[string]QueuedEmail)}
+}
+
+// Enqueue upserts item.
+func (s *InMemoryQueueStore) Enqueue(item QueuedEmail) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[item.ID] = item
+	return nil
+}
+
+// ClaimNext picks an arbitrary ready PENDING item (map iteration order is
+// unspecified, which is fine: nothing here depends on FIFO ordering).
+func (s *InMemoryQueueStore) ClaimNext(now time.Time) (QueuedEmail, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, item := range s.items {
+		if item.Status == QueueStatusPending && !item.NextAttemptAt.After(now) {
+			item.Status = QueueStatusInFlight
+			item.UpdatedAt = now
+			s.items[id] = item
+			return item, true, nil
+		}
+	}
+	return QueuedEmail{}, false, nil
+}
+
+// MarkSent marks id as SENT.
+func (s *InMemoryQueueStore) MarkSent(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[id]
+	if !ok {
+		return fmt.Errorf("queued email %s not found", id)
+	}
+	item.Status = QueueStatusSent
+	item.UpdatedAt = time.Now()
+	s.items[id] = item
+	return nil
+}
+
+// MarkRetry returns id to PENDING, due again at nextAttemptAt.
+func (s *InMemoryQueueStore) MarkRetry(id string, nextAttemptAt time.Time, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[id]
+	if !ok {
+		return fmt.Errorf("queued email %s not found", id)
+	}
+	item.Status = QueueStatusPending
+	item.NextAttemptAt = nextAttemptAt
+	item.LastError = lastErr
+	item.UpdatedAt = time.Now()
+	s.items[id] = item
+	s.retriedTotal++
+	return nil
+}
+
+// MarkDead moves id to the dead-letter state.
+func (s *InMemoryQueueStore) MarkDead(id string, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[id]
+	if !ok {
+		return fmt.Errorf("queued email %s not found", id)
+	}
+	item.Status = QueueStatusDead
+	item.LastError = lastErr
+	item.UpdatedAt = time.Now()
+	s.items[id] = item
+	return nil
+}
+
+// Metrics reports pending/in-flight/retried/dead counts.
+func (s *InMemoryQueueStore) Metrics() QueueMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := QueueMetrics{Retried: s.retriedTotal}
+	for _, item := range s.items {
+		switch item.Status {
+		case QueueStatusPending:
+			m.Pending++
+		case QueueStatusInFlight:
+			m.InFlight++
+		case QueueStatusDead:
+			m.Dead++
+		}
+	}
+	return m
+}
+
+var _ QueueStore = (*InMemoryQueueStore)(nil)
+
+// Queue queues SendTemplate jobs in a QueueStore and drains them with a pool of
+// worker goroutines, retrying transient failures with exponential backoff
+// and jitter before moving a message to the dead-letter state.
+type Queue struct {
+	store   QueueStore
+	service *Service
+	stopCh  chan struct{}
+}
+
+// NewQueue creates a Queue backed by store, delivering each job via
+// service.SendTemplate, and starts workers background goroutines polling the
+// queue. Callers that only need Enqueue/Metrics (e.g. in tests) can pass
+// workers=0 to skip starting any.
+func NewQueue(store QueueStore, service *Service, workers int) *Queue {
+	q := &Queue{store: store, service: service, stopCh: make(chan struct{})}
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+	return q
+}
+
+// Enqueue adds a SendTemplate job for delivery, returning its queue ID
+// immediately without waiting for it to send.
+func (q *Queue) Enqueue(templateName string, toEmail types.Email, data map[string]interface{}) (string, error) {
+	id, err := newQueueID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	item := QueuedEmail{
+		ID:            id,
+		Template:      templateName,
+		ToEmail:       toEmail,
+		Data:          data,
+		Status:        QueueStatusPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		NextAttemptAt: now,
+	}
+	if err := q.store.Enqueue(item); err != nil {
+		return "", fmt.Errorf("failed to enqueue email to %s: %w", toEmail, err)
+	}
+	return id, nil
+}
+
+// SendTemplate enqueues a SendTemplate job and returns immediately,
+// satisfying the same signature Service.SendTemplate exposes so a *Queue can
+// be dropped in wherever a caller (e.g. user.Handler.emailService) expects
+// something that sends templated email, without making the caller block on
+// an SMTP round-trip.
+func (q *Queue) SendTemplate(ctx context.Context, name string, toEmail types.Email, data map[string]interface{}) error {
+	_, err := q.Enqueue(name, toEmail, data)
+	return err
+}
+
+// Metrics reports the queue's current pending/in-flight/retried/dead counts.
+func (q *Queue) Metrics() QueueMetrics {
+	return q.store.Metrics()
+}
+
+// Stop signals every worker goroutine to exit after its current poll.
+func (q *Queue) Stop() {
+	close(q.stopCh)
+}
+
+func (q *Queue) worker() {
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stopCh:
+			return
+		case <-ticker.C:
+			q.drainOne()
+		}
+	}
+}
+
+// drainOne claims and delivers at most one ready item, advancing it to SENT,
+// back to PENDING with a backed-off NextAttemptAt, or to DEAD once
+// queueMaxAttempts is exhausted.
+func (q *Queue) drainOne() {
+	item, ok, err := q.store.ClaimNext(time.Now())
+	if err != nil {
+		log.Printf("email queue: failed to claim next job: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	if err := q.service.SendTemplate(context.Background(), item.Template, item.ToEmail, item.Data); err != nil {
+		item.Attempts++
+		if item.Attempts >= queueMaxAttempts {
+			if dlqErr := q.store.MarkDead(item.ID, err.Error()); dlqErr != nil {
+				log.Printf("email queue: failed to dead-letter job %s: %v", item.ID, dlqErr)
+			}
+			return
+		}
+		if retryErr := q.store.MarkRetry(item.ID, time.Now().Add(backoffWithJitter(item.Attempts)), err.Error()); retryErr != nil {
+			log.Printf("email queue: failed to schedule retry for job %s: %v", item.ID, retryErr)
+		}
+		return
+	}
+
+	if err := q.store.MarkSent(item.ID); err != nil {
+		log.Printf("email queue: failed to mark job %s sent: %v", item.ID, err)
+	}
+}
+
+// backoffWithJitter returns queueBaseDelay*2^(attempt-1) (capped at
+// queueMaxDelay) with up to 50% jitter.
+func backoffWithJitter(attempt int) time.Duration {
+	delay := queueBaseDelay << (attempt - 1)
+	if attempt <= 0 || delay <= 0 || delay > queueMaxDelay {
+		delay = queueMaxDelay
+	}
+	return delay/2 + randDuration(delay/2)
+}
+
+// randDuration returns a random duration in [0, max), using crypto/rand like
+// the rest of this codebase's token/nonce generation rather than math/rand.
+func randDuration(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0
+	}
+	n := binary.BigEndian.Uint64(b[:]) % uint64(max)
+	return time.Duration(n)
+}
+
+// newQueueID generates a random identifier for a QueuedEmail.
+func newQueueID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate queue id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
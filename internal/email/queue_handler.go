@@ -0,0 +1,42 @@
+package email
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// QueueHandler exposes an admin API for inspecting the email queue. Kept
+// separate from Handler (which wraps sending, not queue operations) so it
+// doesn't inherit Handler's unrelated send-path dependencies. Callers should
+// guard RegisterRoutes' group with user.RequireAdmin() (this package can't
+// import user directly: user doesn't depend on email's queue, and neither
+// should depend on the other).
+type QueueHandler struct {
+	queue *Queue
+}
+
+// NewQueueHandler creates a handler backed by the given Queue.
+func NewQueueHandler(queue *Queue) *QueueHandler {
+	return &QueueHandler{queue: queue}
+}
+
+// RegisterRoutes attaches queue routes to the supplied router group.
+func (h *QueueHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/email/queue", h.GetQueueMetrics)
+}
+
+// GetQueueMetrics godoc
+// @Summary      Get email queue metrics
+// @Description  Get the current pending/in-flight/retried/dead counts for the outbound email queue (requires admin role)
+// @Tags         email
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "Queue metrics retrieved successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Router       /admin/email/queue [get]
+func (h *QueueHandler) GetQueueMetrics(c *gin.Context) {
+	response.Success(c, http.StatusOK, h.queue.Metrics(), "Queue metrics retrieved successfully")
+}
@@ -1,13 +1,22 @@
 package email
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/tls"
 	"encoding/hex"
 	"fmt"
+	"mime/multipart"
+	"mime/quotedprintable"
 	"net"
 	"net/smtp"
+	"net/textproto"
 	"strings"
+	"time"
+
+	"lam-phuong-api/internal/logger"
+	"lam-phuong-api/internal/types"
 )
 
 // Service handles email sending via SMTP relay
@@ -16,9 +25,12 @@ type Service struct {
 	smtpPort     string
 	smtpUsername string
 	smtpPassword string
-	fromEmail    string
+	fromEmail    types.Email
 	fromName     string
 	useTLS       bool // Use TLS for SMTP connection
+	tlsConfig    *tls.Config
+	templates    *Registry
+	locale       string
 }
 
 // NewService creates a new email service with TLS enabled by default
@@ -33,36 +45,122 @@ func NewServiceWithTLS(smtpHost, smtpPort, smtpUsername, smtpPassword, fromEmail
 		smtpPort:     smtpPort,
 		smtpUsername: smtpUsername,
 		smtpPassword: smtpPassword,
-		fromEmail:    fromEmail,
+		fromEmail:    normalizeOrRaw(fromEmail),
 		fromName:     fromName,
 		useTLS:       useTLS,
+		templates:    defaultTemplates,
+		locale:       "en",
+	}
+}
+
+// NewServiceWithTLSConfig creates a new email service that dials with tlsConfig
+// instead of the bare {ServerName: smtpHost} deliver builds by default, for
+// relays that need custom root CAs, a minimum TLS version, or (via
+// clientCertFile/clientKeyFile) a client certificate for mTLS. Pass "" for
+// clientCertFile/clientKeyFile to skip client-certificate loading; tlsConfig
+// itself may be nil, in which case only the client certificate (if any) is
+// configured and ServerName still defaults to smtpHost at dial time.
+func NewServiceWithTLSConfig(smtpHost, smtpPort, smtpUsername, smtpPassword, fromEmail, fromName string, tlsConfig *tls.Config, clientCertFile, clientKeyFile string) (*Service, error) {
+	s := NewServiceWithTLS(smtpHost, smtpPort, smtpUsername, smtpPassword, fromEmail, fromName, true)
+
+	if tlsConfig != nil {
+		cfg := tlsConfig.Clone()
+		s.tlsConfig = cfg
+	}
+
+	if clientCertFile != "" || clientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SMTP client certificate: %w", err)
+		}
+		if s.tlsConfig == nil {
+			s.tlsConfig = &tls.Config{}
+		}
+		s.tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return s, nil
+}
+
+// dialTLSConfig returns the *tls.Config to dial or StartTLS with: a clone of
+// s.tlsConfig (so Certificates from NewServiceWithTLSConfig survive) with
+// ServerName filled in, or a bare {ServerName: s.smtpHost} when no tlsConfig
+// was configured.
+func (s *Service) dialTLSConfig() *tls.Config {
+	if s.tlsConfig == nil {
+		return &tls.Config{ServerName: s.smtpHost}
+	}
+	cfg := s.tlsConfig.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = s.smtpHost
 	}
+	return cfg
 }
 
-// SendVerificationEmail sends an email verification email to the user
-func (s *Service) SendVerificationEmail(toEmail, verificationToken, baseURL string) error {
-	verificationURL := fmt.Sprintf("%s/api/auth/verify-email?token=%s", baseURL, verificationToken)
+// normalizeOrRaw normalizes raw into a types.Email, falling back to a
+// lowercased/trimmed-but-unvalidated Email if raw isn't a parseable address
+// (e.g. a placeholder from-address in local dev config) rather than
+// rejecting construction outright - isValidEmail still catches it at send
+// time, same as the original behavior.
+func normalizeOrRaw(raw string) types.Email {
+	e, err := types.NewEmail(raw)
+	if err != nil {
+		return types.Email(strings.ToLower(strings.TrimSpace(raw)))
+	}
+	return e
+}
 
-	subject := "Verify Your Email Address"
-	body := fmt.Sprintf(`Hello,
+// SetTemplateRegistry overrides the built-in templates, e.g. with a Registry
+// loaded from an on-disk override directory instead of the embedded one.
+func (s *Service) SetTemplateRegistry(registry *Registry) {
+	s.templates = registry
+}
 
-Thank you for registering! Please verify your email address by clicking the link below:
+// SetLocale sets the locale SendTemplate renders with. Unset, it defaults
+// to "en".
+func (s *Service) SetLocale(locale string) {
+	s.locale = locale
+}
 
-%s
+// SendTemplate renders the named template (see internal/email/templates)
+// against data and sends the result as a multipart/alternative email. This
+// is the only path the user package should use for outgoing mail; verification
+// and password reset both go through it. data's "FromName" key is filled in
+// from the service's configured from-name when the caller doesn't set it.
+func (s *Service) SendTemplate(ctx context.Context, name string, toEmail types.Email, data map[string]interface{}) error {
+	merged := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		merged[k] = v
+	}
+	if _, ok := merged["FromName"]; !ok {
+		merged["FromName"] = s.fromName
+	}
 
-This link will expire in 24 hours.
+	log := logger.FromContext(ctx)
 
-If you did not create an account, please ignore this email.
+	subject, text, html, err := s.templates.Render(name, s.locale, merged)
+	if err != nil {
+		log.Error().Err(err).Str("template", name).Msg("failed to render email template")
+		return err
+	}
 
-Best regards,
-%s`, verificationURL, s.fromName)
+	if err := s.sendMultipart(toEmail, subject, text, html); err != nil {
+		log.Warn().Err(err).Str("template", name).Str("to", string(toEmail)).Msg("failed to send templated email")
+		return err
+	}
+	return nil
+}
 
+// Send sends an arbitrary subject/body plain-text email to toEmail, for
+// callers (such as internal/job's bulk email batch) that don't need a
+// registered template.
+func (s *Service) Send(toEmail types.Email, subject, body string) error {
 	return s.sendEmail(toEmail, subject, body)
 }
 
 // sendEmail sends an email using SMTP relay
 // Authentication is optional - works with open relays or authenticated SMTP servers
-func (s *Service) sendEmail(toEmail, subject, body string) error {
+func (s *Service) sendEmail(toEmail types.Email, subject, body string) error {
 	// If SMTP is not configured, log and skip sending (for development)
 	if s.smtpHost == "" || s.smtpPort == "" {
 		fmt.Printf("[EMAIL] Would send email to %s\n", toEmail)
@@ -71,7 +169,6 @@ func (s *Service) sendEmail(toEmail, subject, body string) error {
 		return nil
 	}
 
-	// Validate email addresses
 	if !isValidEmail(toEmail) {
 		return fmt.Errorf("invalid recipient email address: %s", toEmail)
 	}
@@ -79,31 +176,112 @@ func (s *Service) sendEmail(toEmail, subject, body string) error {
 		return fmt.Errorf("invalid sender email address: %s", s.fromEmail)
 	}
 
-	addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
+	var message bytes.Buffer
+	message.WriteString(s.commonHeaders(toEmail, subject))
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(body)
+
+	return s.deliver(toEmail, message.Bytes())
+}
+
+// sendMultipart sends a multipart/alternative email with both a text and an
+// HTML body, each quoted-printable encoded, for SendTemplate.
+func (s *Service) sendMultipart(toEmail types.Email, subject, textBody, htmlBody string) error {
+	if s.smtpHost == "" || s.smtpPort == "" {
+		fmt.Printf("[EMAIL] Would send email to %s\n", toEmail)
+		fmt.Printf("[EMAIL] Subject: %s\n", subject)
+		fmt.Printf("[EMAIL] Text body: %s\n", textBody)
+		return nil
+	}
 
-	// Create email message with proper headers
-	from := s.fromEmail
+	if !isValidEmail(toEmail) {
+		return fmt.Errorf("invalid recipient email address: %s", toEmail)
+	}
+	if !isValidEmail(s.fromEmail) {
+		return fmt.Errorf("invalid sender email address: %s", s.fromEmail)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	if err := writeQuotedPrintablePart(mw, "text/plain; charset=UTF-8", textBody); err != nil {
+		return fmt.Errorf("failed to write text part: %w", err)
+	}
+	if err := writeQuotedPrintablePart(mw, "text/html; charset=UTF-8", htmlBody); err != nil {
+		return fmt.Errorf("failed to write html part: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	var message bytes.Buffer
+	message.WriteString(s.commonHeaders(toEmail, subject))
+	message.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q\r\n", mw.Boundary()))
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return s.deliver(toEmail, message.Bytes())
+}
+
+// writeQuotedPrintablePart adds one quoted-printable-encoded part to mw.
+func writeQuotedPrintablePart(mw *multipart.Writer, contentType, content string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	qpw := quotedprintable.NewWriter(part)
+	if _, err := qpw.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qpw.Close()
+}
+
+// commonHeaders builds the header block shared by every outgoing message:
+// From/To/Subject/MIME-Version plus Date, Message-Id and Reply-To, which
+// the original implementation didn't set.
+func (s *Service) commonHeaders(toEmail types.Email, subject string) string {
+	from := string(s.fromEmail)
 	if s.fromName != "" {
 		from = fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail)
 	}
 
-	// Build email message with proper headers
-	headers := make(map[string]string)
-	headers["From"] = from
-	headers["To"] = toEmail
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/plain; charset=UTF-8"
-	headers["Content-Transfer-Encoding"] = "8bit"
+	var h strings.Builder
+	h.WriteString(fmt.Sprintf("From: %s\r\n", from))
+	h.WriteString(fmt.Sprintf("To: %s\r\n", toEmail))
+	h.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	h.WriteString(fmt.Sprintf("Reply-To: %s\r\n", s.fromEmail))
+	h.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	h.WriteString(fmt.Sprintf("Message-Id: %s\r\n", s.newMessageID()))
+	h.WriteString("MIME-Version: 1.0\r\n")
+	return h.String()
+}
+
+// newMessageID generates a Message-Id header value unique to this send,
+// scoped to the sender's domain (or "localhost" if fromEmail has none).
+func (s *Service) newMessageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
 
-	// Build message
-	message := ""
-	for k, v := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
+	domain := "localhost"
+	if parts := strings.SplitN(string(s.fromEmail), "@", 2); len(parts) == 2 && parts[1] != "" {
+		domain = parts[1]
 	}
-	message += "\r\n" + body
 
-	msg := []byte(message)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// deliver opens an SMTP session to s.smtpHost:s.smtpPort, authenticates if
+// credentials are configured, and transmits msg to toEmail. Shared by
+// sendEmail and sendMultipart, which differ only in how msg is built.
+func (s *Service) deliver(toEmail types.Email, msg []byte) error {
+	addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
 
 	// Connect to SMTP server
 	var client *smtp.Client
@@ -120,11 +298,11 @@ func (s *Service) sendEmail(toEmail, subject, body string) error {
 	}
 
 	// Port 465 uses direct TLS connection
+	usedClientCert := false
 	if port == "465" && s.useTLS {
 		// Use direct TLS connection for port 465
-		tlsConfig := &tls.Config{
-			ServerName: s.smtpHost,
-		}
+		tlsConfig := s.dialTLSConfig()
+		usedClientCert = len(tlsConfig.Certificates) > 0
 
 		conn, err := tls.Dial("tcp", addr, tlsConfig)
 		if err != nil {
@@ -152,12 +330,11 @@ func (s *Service) sendEmail(toEmail, subject, body string) error {
 		// Upgrade to STARTTLS if TLS is enabled and server supports it
 		if s.useTLS {
 			if ok, _ := client.Extension("STARTTLS"); ok {
-				tlsConfig := &tls.Config{
-					ServerName: s.smtpHost,
-				}
+				tlsConfig := s.dialTLSConfig()
 				if err = client.StartTLS(tlsConfig); err != nil {
 					return fmt.Errorf("failed to start TLS: %w", err)
 				}
+				usedClientCert = len(tlsConfig.Certificates) > 0
 			} else {
 				// Server doesn't support STARTTLS, but TLS was requested
 				// This is OK - continue without TLS upgrade
@@ -167,43 +344,38 @@ func (s *Service) sendEmail(toEmail, subject, body string) error {
 
 	defer client.Close()
 
-	// Authenticate if credentials are provided
-	// Only authenticate if server supports AUTH extension
-	if s.smtpUsername != "" && s.smtpPassword != "" {
-		// Check if server supports authentication
-		if ok, authMethods := client.Extension("AUTH"); ok {
-			// Check if PLAIN auth is supported
-			supportsPlain := false
-			if authMethods != "" {
-				// authMethods might be something like "PLAIN LOGIN" or "PLAIN"
-				if strings.Contains(strings.ToUpper(authMethods), "PLAIN") {
-					supportsPlain = true
-				}
-			} else {
-				// If no methods listed, assume PLAIN is supported
-				supportsPlain = true
-			}
+	// Authenticate: prefer EXTERNAL (the TLS client cert already proved our
+	// identity) when we presented one and the server advertises it; otherwise
+	// fall back through PLAIN, CRAM-MD5, and LOGIN in the order picked by
+	// authMethodFor, same as before for servers that only ever offered PLAIN.
+	if ok, authMethods := client.Extension("AUTH"); ok {
+		upper := strings.ToUpper(authMethods)
 
-			if supportsPlain {
-				auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
+		if usedClientCert && strings.Contains(upper, "EXTERNAL") {
+			if err = client.Auth(externalAuth{identity: s.smtpUsername}); err != nil {
+				return fmt.Errorf("SMTP EXTERNAL authentication failed: %w", err)
+			}
+		} else if s.smtpUsername != "" && s.smtpPassword != "" {
+			auth := authMethodFor(upper, s.smtpHost, s.smtpUsername, s.smtpPassword)
+			if auth != nil {
 				if err = client.Auth(auth); err != nil {
-					// Authentication failed
 					// EOF error usually means server closed connection - might not support auth on plain connection
 					// or credentials are wrong
 					return fmt.Errorf("SMTP authentication failed: %w", err)
 				}
 			}
+			// If none of our supported mechanisms were advertised, continue
+			// without authentication (open relay).
 		}
-		// If server doesn't support AUTH extension, continue without authentication (open relay)
 	}
 
 	// Set sender
-	if err = client.Mail(s.fromEmail); err != nil {
+	if err = client.Mail(string(s.fromEmail)); err != nil {
 		return fmt.Errorf("failed to set sender: %w", err)
 	}
 
 	// Set recipient
-	if err = client.Rcpt(toEmail); err != nil {
+	if err = client.Rcpt(string(toEmail)); err != nil {
 		return fmt.Errorf("failed to set recipient: %w", err)
 	}
 
@@ -233,12 +405,74 @@ func (s *Service) sendEmail(toEmail, subject, body string) error {
 	return nil
 }
 
+// authMethodFor picks an smtp.Auth from upper (the server's advertised AUTH
+// mechanisms, already upper-cased), preferring PLAIN when it's supported (or
+// when the server lists no mechanisms at all, matching this package's
+// original behavior of assuming PLAIN), then CRAM-MD5, then LOGIN. Returns
+// nil if none of these three are advertised.
+func authMethodFor(upper, host, username, password string) smtp.Auth {
+	switch {
+	case upper == "" || strings.Contains(upper, "PLAIN"):
+		return smtp.PlainAuth("", username, password, host)
+	case strings.Contains(upper, "CRAM-MD5"):
+		return smtp.CRAMMD5Auth(username, password)
+	case strings.Contains(upper, "LOGIN"):
+		return loginAuth{username: username, password: password}
+	default:
+		return nil
+	}
+}
+
+// loginAuth implements the AUTH LOGIN mechanism, which net/smtp doesn't
+// provide (it only ships PLAIN and CRAM-MD5), for relays that advertise
+// LOGIN but not PLAIN.
+type loginAuth struct {
+	username, password string
+}
+
+func (a loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	return "LOGIN", nil, nil
+}
+
+func (a loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSuffix(string(fromServer), ":")) {
+	case "username":
+		return []byte(a.username), nil
+	case "password":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN server prompt: %q", fromServer)
+	}
+}
+
+// externalAuth implements the AUTH EXTERNAL mechanism (RFC 4422 appendix A):
+// the client has already authenticated at the TLS layer via a client
+// certificate, so the single response is just the identity to act as (empty
+// to let the server derive it from the certificate).
+type externalAuth struct {
+	identity string
+}
+
+func (a externalAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	return "EXTERNAL", []byte(a.identity), nil
+}
+
+func (a externalAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		return nil, fmt.Errorf("unexpected continuation during EXTERNAL auth")
+	}
+	return nil, nil
+}
+
 // isValidEmail performs basic email validation
-func isValidEmail(email string) bool {
+func isValidEmail(email types.Email) bool {
 	if email == "" {
 		return false
 	}
-	parts := strings.Split(email, "@")
+	parts := strings.Split(string(email), "@")
 	if len(parts) != 2 {
 		return false
 	}
@@ -0,0 +1,149 @@
+package email
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+
+	"lam-phuong-api/internal/email/templates"
+)
+
+// templatePair holds the subject/text/html variants registered for one
+// template name in one locale. A template is only usable once all three are
+// present.
+type templatePair struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Registry holds the subject/text/html template triples for every named
+// transactional email (verification, password_reset, welcome, admin_invite,
+// ...), loaded from a filesystem tree shaped like:
+//
+//	<locale>/<name>.subject.tmpl
+//	<locale>/<name>.txt.tmpl
+//	<locale>/<name>.html.tmpl
+//
+// defaultLocale is used whenever Render is asked for a locale that has no
+// override for the requested name.
+type Registry struct {
+	defaultLocale string
+	pairs         map[string]map[string]templatePair // name -> locale -> pair
+}
+
+// defaultTemplates is the registry built from the templates embedded in
+// this binary. Service uses it unless SetTemplateRegistry overrides it.
+var defaultTemplates = mustLoadTemplates()
+
+func mustLoadTemplates() *Registry {
+	r, err := NewRegistry(templates.FS, "en")
+	if err != nil {
+		panic(fmt.Sprintf("email: failed to parse built-in templates: %v", err))
+	}
+	return r
+}
+
+// NewRegistry walks fsys and parses every "<locale>/<name>.{subject,txt,html}.tmpl"
+// file it finds into a Registry.
+func NewRegistry(fsys fs.FS, defaultLocale string) (*Registry, error) {
+	r := &Registry{
+		defaultLocale: defaultLocale,
+		pairs:         make(map[string]map[string]templatePair),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		name, kind, ok := parseTemplateFilename(path.Base(p))
+		if !ok {
+			return nil
+		}
+		locale := path.Dir(p)
+
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("email: failed to read template %q: %w", p, err)
+		}
+
+		if r.pairs[name] == nil {
+			r.pairs[name] = make(map[string]templatePair)
+		}
+		pair := r.pairs[name][locale]
+
+		switch kind {
+		case "subject":
+			pair.subject, err = texttemplate.New(name + ".subject").Parse(string(content))
+		case "txt":
+			pair.text, err = texttemplate.New(name + ".txt").Parse(string(content))
+		case "html":
+			pair.html, err = htmltemplate.New(name + ".html").Parse(string(content))
+		}
+		if err != nil {
+			return fmt.Errorf("email: failed to parse template %q: %w", p, err)
+		}
+
+		r.pairs[name][locale] = pair
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// parseTemplateFilename splits "verification.subject.tmpl" into
+// ("verification", "subject", true), and reports ok=false for anything not
+// shaped like "<name>.<kind>.tmpl".
+func parseTemplateFilename(filename string) (name, kind string, ok bool) {
+	for _, k := range []string{"subject", "txt", "html"} {
+		suffix := "." + k + ".tmpl"
+		if strings.HasSuffix(filename, suffix) {
+			return strings.TrimSuffix(filename, suffix), k, true
+		}
+	}
+	return "", "", false
+}
+
+// Render executes the subject/text/html templates registered under name for
+// locale, falling back to the registry's default locale if locale has no
+// override. data is passed to all three templates unchanged.
+func (r *Registry) Render(name, locale string, data interface{}) (subject, text, html string, err error) {
+	locales, ok := r.pairs[name]
+	if !ok {
+		return "", "", "", fmt.Errorf("email: unknown template %q", name)
+	}
+
+	pair, ok := locales[locale]
+	if !ok {
+		pair, ok = locales[r.defaultLocale]
+		if !ok {
+			return "", "", "", fmt.Errorf("email: no %q template for locale %q or default locale %q", name, locale, r.defaultLocale)
+		}
+	}
+	if pair.subject == nil || pair.text == nil || pair.html == nil {
+		return "", "", "", fmt.Errorf("email: template %q is missing its subject, text or html variant", name)
+	}
+
+	var subjectBuf, textBuf, htmlBuf strings.Builder
+	if err := pair.subject.Execute(&subjectBuf, data); err != nil {
+		return "", "", "", fmt.Errorf("email: failed to render subject for template %q: %w", name, err)
+	}
+	if err := pair.text.Execute(&textBuf, data); err != nil {
+		return "", "", "", fmt.Errorf("email: failed to render text body for template %q: %w", name, err)
+	}
+	if err := pair.html.Execute(&htmlBuf, data); err != nil {
+		return "", "", "", fmt.Errorf("email: failed to render html body for template %q: %w", name, err)
+	}
+
+	return strings.TrimSpace(subjectBuf.String()), textBuf.String(), htmlBuf.String(), nil
+}
@@ -0,0 +1,10 @@
+// Package templates embeds the built-in email template tree so the email
+// package can load it without depending on a filesystem path at runtime.
+// Adding a locale is a matter of adding a new top-level directory here and
+// listing it in the //go:embed directive below.
+package templates
+
+import "embed"
+
+//go:embed en
+var FS embed.FS
@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// CSVImportResult summarizes a bulk CSV import job's outcome.
+type CSVImportResult struct {
+	Created int      `json:"created"`
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// SubmitCSVImport parses r as CSV (a header row followed by one row per
+// record), maps each data row to a T via parseRow, creates it via create,
+// and submits the whole batch as a single job of jobType. Used for
+// bulk-importing JobType/Location records; see jobtype.ImportJobTypes and
+// location.ImportLocations.
+func SubmitCSVImport[T any](s *Service, jobType Type, resourceGUID string, r io.Reader, parseRow func(row []string) (T, error), create func(ctx context.Context, item T) (T, error)) (string, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(rows) < 2 {
+		return "", fmt.Errorf("CSV must contain a header row and at least one data row")
+	}
+	dataRows := rows[1:] // skip header
+
+	return s.Submit(jobType, resourceGUID, func(ctx context.Context) (interface{}, error) {
+		result := CSVImportResult{}
+		for _, row := range dataRows {
+			item, err := parseRow(row)
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+			if _, err := create(ctx, item); err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+			result.Created++
+		}
+		if result.Failed > 0 && result.Created == 0 {
+			return result, fmt.Errorf("all %d rows failed to import", result.Failed)
+		}
+		return result, nil
+	})
+}
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"context"
+	"fmt"
+
+	"lam-phuong-api/internal/types"
+)
+
+// EmailSender is the subset of email.Service's API used by SubmitEmailBatch.
+// Declared here instead of imported so internal/job doesn't depend on
+// internal/email; *email.Service satisfies it structurally.
+type EmailSender interface {
+	Send(toEmail types.Email, subject, body string) error
+}
+
+// EmailBatchResult summarizes a bulk email send job's outcome.
+type EmailBatchResult struct {
+	Sent   int      `json:"sent"`
+	Failed int      `json:"failed"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SubmitEmailBatch submits sending subject/body to every address in
+// recipients as a single TypeBulkSendEmail job.
+func SubmitEmailBatch(s *Service, sender EmailSender, resourceGUID, subject, body string, recipients []string) (string, error) {
+	return s.Submit(TypeBulkSendEmail, resourceGUID, func(ctx context.Context) (interface{}, error) {
+		result := EmailBatchResult{}
+		for _, to := range recipients {
+			email, err := types.NewEmail(to)
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", to, err))
+				continue
+			}
+			if err := sender.Send(email, subject, body); err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", to, err))
+				continue
+			}
+			result.Sent++
+		}
+		if result.Failed > 0 && result.Sent == 0 {
+			return result, fmt.Errorf("all %d emails failed to send", result.Failed)
+		}
+		return result, nil
+	})
+}
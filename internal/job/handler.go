@@ -0,0 +1,79 @@
+package job
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// Presenter shapes a completed Job's Result for a specific Type before it is
+// returned to the client. Packages that submit jobs of a given Type
+// register a Presenter for it via RegisterPresenter.
+type Presenter func(j Job) interface{}
+
+var presenters = map[Type]Presenter{}
+
+// RegisterPresenter associates a Presenter with a job Type. Call this from
+// the package that owns the job's result shape, typically from an init().
+func RegisterPresenter(t Type, p Presenter) {
+	presenters[t] = p
+}
+
+// Handler exposes the generic job-status endpoint.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a handler backed by the given Service.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes attaches job routes to the supplied router group.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/jobs/:guid", h.GetJob)
+}
+
+// GetJob godoc
+// @Summary      Get job status
+// @Description  Poll the status of an asynchronous job by its GUID (requires authentication)
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        guid  path      string  true  "Job GUID"
+// @Success      200   {object}  response.Response  "Job retrieved successfully"
+// @Failure      401   {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      404   {object}  response.ErrorResponse  "Job not found"
+// @Router       /jobs/{guid} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	guid := c.Param("guid")
+	if guid == "" {
+		response.BadRequest(c, "Job GUID is required", nil)
+		return
+	}
+
+	j, ok := h.service.Get(guid)
+	if !ok {
+		response.NotFound(c, "Job")
+		return
+	}
+
+	if j.Status == StatusComplete {
+		t := typeFromGUID(guid)
+		if present, ok := presenters[t]; ok {
+			j.Result = present(j)
+		}
+		j.ResourceURL = resourceURL(t, j.Result)
+	}
+
+	response.Success(c, http.StatusOK, j, "Job retrieved successfully")
+}
+
+// typeFromGUID extracts the job type prefix from a "<type>.<resourceGUID>" GUID.
+func typeFromGUID(guid string) Type {
+	parts := strings.SplitN(guid, ".", 2)
+	return Type(parts[0])
+}
@@ -0,0 +1,45 @@
+package job
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// resourcePaths maps a job Type to the REST path its affected resource lives
+// under, e.g. "/api/locations". GetJob uses it to attach a ResourceURL to
+// the response when the completed job's Result carries a Slug field.
+var resourcePaths = map[Type]string{}
+
+// RegisterResourcePath associates a Type with the REST path its resource is
+// served from, so GetJob can link straight to it. Call this from the
+// package that owns the resource, typically from the same init() that calls
+// RegisterPresenter.
+func RegisterResourcePath(t Type, path string) {
+	resourcePaths[t] = path
+}
+
+// resourceURL derives a link to the resource a completed job of type t
+// affected, by looking for a "Slug" field (struct) or "slug" key (map) on
+// result. Returns "" if no path is registered or no slug can be found.
+func resourceURL(t Type, result interface{}) string {
+	base, ok := resourcePaths[t]
+	if !ok || result == nil {
+		return ""
+	}
+
+	v := reflect.ValueOf(result)
+	switch v.Kind() {
+	case reflect.Map:
+		for _, key := range []string{"slug", "Slug"} {
+			mv := v.MapIndex(reflect.ValueOf(key))
+			if mv.IsValid() {
+				return fmt.Sprintf("%s/%v", base, mv.Interface())
+			}
+		}
+	case reflect.Struct:
+		if f := v.FieldByName("Slug"); f.IsValid() {
+			return fmt.Sprintf("%s/%v", base, f.Interface())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package job
+
+import "time"
+
+// Status represents the lifecycle state of an asynchronous job.
+type Status string
+
+// Job lifecycle states.
+const (
+	StatusProcessing Status = "PROCESSING"
+	StatusComplete   Status = "COMPLETE"
+	StatusFailed     Status = "FAILED"
+)
+
+// Type identifies the kind of work a job performs. It doubles as the GUID
+// prefix used to route status lookups to the right presenter.
+type Type string
+
+// Known job types. Packages that submit new kinds of work should add a
+// constant here and register a Presenter in init().
+const (
+	TypeBulkImportLocations Type = "bulk_import_locations"
+	TypeBulkImportJobTypes  Type = "bulk_import_job_types"
+	TypeBulkSendEmail       Type = "bulk_send_email"
+	TypeSyncAirtable        Type = "sync_airtable"
+	TypeLocationWrite       Type = "location_write"
+	TypeJobTypeWrite        Type = "job_type_write"
+	TypeJobCategoryWrite    Type = "job_category_write"
+)
+
+// Job tracks the state of a single asynchronous operation. The GUID has the
+// form "<type>.<resourceGUID>" so a status lookup can be routed back to the
+// package that submitted the work without a central registry of resources.
+type Job struct {
+	GUID      string      `json:"guid"`
+	Type      Type        `json:"type"`
+	Status    Status      `json:"status"`
+	Errors    []string    `json:"errors,omitempty"`
+	Result    interface{} `json:"result,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	// ResourceURL links to the resource a completed job affected, derived
+	// from Result via a path registered with RegisterResourcePath. It's
+	// computed when the job is read back, not persisted by the Store.
+	ResourceURL string `json:"resource_url,omitempty"`
+}
@@ -0,0 +1,18 @@
+package job
+
+import "github.com/gin-gonic/gin"
+
+// WantsAsync decides whether a mutating request should run as a background
+// job instead of synchronously. Precedence: an explicit ?async= query param
+// always wins; otherwise a "Prefer: respond-async=false" header opts out of
+// an enabled default; with neither present, defaultAsync (driven by
+// Config.Jobs.DefaultAsync) decides.
+func WantsAsync(c *gin.Context, defaultAsync bool) bool {
+	if async := c.Query("async"); async != "" {
+		return async == "true"
+	}
+	if c.GetHeader("Prefer") == "respond-async=false" {
+		return false
+	}
+	return defaultAsync
+}
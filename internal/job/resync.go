@@ -0,0 +1,17 @@
+package job
+
+import "context"
+
+// SubmitResync submits warmup (e.g. an AirtableRepository's Warmup) as a
+// single TypeSyncAirtable job, giving a manually-triggered cache resync the
+// same retry/backoff and status polling as other async work. The cron-driven
+// resync in internal/sync calls warmup directly instead, since scheduled
+// runs have no caller polling for a GUID.
+func SubmitResync(s *Service, resourceGUID string, warmup func(ctx context.Context) error) (string, error) {
+	return s.Submit(TypeSyncAirtable, resourceGUID, func(ctx context.Context) (interface{}, error) {
+		if err := warmup(ctx); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"resynced": true}, nil
+	})
+}
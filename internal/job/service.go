@@ -0,0 +1,132 @@
+package job
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Retry tuning for transient Work failures. A job is only marked FAILED
+// after maxAttempts have all errored; each retry backs off by
+// retryBaseDelay * 2^(attempt-1).
+const (
+	maxAttempts    = 3
+	retryBaseDelay = 200 * time.Millisecond
+)
+
+// Work is a unit of asynchronous work submitted to the Service. Its return
+// value becomes the Job's Result once the job completes.
+type Work func(ctx context.Context) (interface{}, error)
+
+type task struct {
+	job  Job
+	work Work
+}
+
+// Service dispatches submitted Work to a fixed pool of worker goroutines
+// draining an internal channel and records progress in a Store.
+type Service struct {
+	store Store
+	queue chan task
+}
+
+// NewService creates a Service backed by store with the given number of
+// worker goroutines. Workers recover from panics in submitted Work so one
+// bad job cannot take down the pool.
+func NewService(store Store, workers int) *Service {
+	if workers <= 0 {
+		workers = 1
+	}
+	s := &Service{
+		store: store,
+		queue: make(chan task, 256),
+	}
+	for i := 0; i < workers; i++ {
+		go s.worker()
+	}
+	return s
+}
+
+// Submit enqueues work under a job GUID of the form "<type>.<resourceGUID>"
+// and returns that GUID immediately. The job starts in PROCESSING state.
+func (s *Service) Submit(jobType Type, resourceGUID string, work Work) (string, error) {
+	guid := fmt.Sprintf("%s.%s", jobType, resourceGUID)
+	now := time.Now()
+	j := Job{
+		GUID:      guid,
+		Type:      jobType,
+		Status:    StatusProcessing,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.Save(j); err != nil {
+		return "", fmt.Errorf("failed to persist job %s: %w", guid, err)
+	}
+
+	s.queue <- task{job: j, work: work}
+	return guid, nil
+}
+
+// Get returns the current state of a job by GUID.
+func (s *Service) Get(guid string) (Job, bool) {
+	return s.store.Get(guid)
+}
+
+// NewResourceGUID generates a random identifier suitable for the
+// "<resourceGUID>" half of a job GUID when the resource doesn't already
+// have one (e.g. a bulk operation with no single owning record).
+func NewResourceGUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate resource guid: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *Service) worker() {
+	for t := range s.queue {
+		s.run(t)
+	}
+}
+
+func (s *Service) run(t task) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("job %s panicked: %v", t.job.GUID, r)
+			t.job.Status = StatusFailed
+			t.job.Errors = append(t.job.Errors, fmt.Sprintf("panic: %v", r))
+			t.job.UpdatedAt = time.Now()
+			if err := s.store.Save(t.job); err != nil {
+				log.Printf("failed to save panicked job %s: %v", t.job.GUID, err)
+			}
+		}
+	}()
+
+	var result interface{}
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result, err = t.work(context.Background())
+		if err == nil {
+			break
+		}
+		if attempt < maxAttempts {
+			time.Sleep(retryBaseDelay << (attempt - 1))
+		}
+	}
+
+	t.job.UpdatedAt = time.Now()
+	if err != nil {
+		t.job.Status = StatusFailed
+		t.job.Errors = append(t.job.Errors, fmt.Sprintf("failed after %d attempts: %s", maxAttempts, err.Error()))
+	} else {
+		t.job.Status = StatusComplete
+		t.job.Result = result
+	}
+
+	if err := s.store.Save(t.job); err != nil {
+		log.Printf("failed to save job %s: %v", t.job.GUID, err)
+	}
+}
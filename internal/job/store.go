@@ -0,0 +1,40 @@
+package job
+
+import "sync"
+
+// Store persists job state, keyed by GUID.
+type Store interface {
+	Save(j Job) error
+	Get(guid string) (Job, bool)
+}
+
+// InMemoryStore is a Store implementation backed by a map, safe for
+// concurrent use. A future AirtableStore can satisfy the same interface to
+// persist job state across restarts.
+type InMemoryStore struct {
+	mu   sync.RWMutex
+	data map[string]Job
+}
+
+// NewInMemoryStore creates an empty in-memory job store.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{data: make(map[string]Job)}
+}
+
+// Save upserts a job's state.
+func (s *InMemoryStore) Save(j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[j.GUID] = j
+	return nil
+}
+
+// Get retrieves a job by GUID.
+func (s *InMemoryStore) Get(guid string) (Job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	j, ok := s.data[guid]
+	return j, ok
+}
+
+var _ Store = (*InMemoryStore)(nil)
@@ -1,17 +1,30 @@
 package jobcategory
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gosimple/slug"
+	"lam-phuong-api/internal/job"
 	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/slugindex"
 )
 
+func init() {
+	job.RegisterPresenter(job.TypeJobCategoryWrite, func(j job.Job) interface{} {
+		return j.Result
+	})
+	job.RegisterResourcePath(job.TypeJobCategoryWrite, "/api/job-categories")
+}
+
 // Handler exposes HTTP handlers for the job category resource.
 type Handler struct {
-	repo Repository
+	repo         Repository
+	jobService   *job.Service
+	defaultAsync bool
+	slugIndex    *slugindex.Index
 }
 
 // NewHandler creates a handler with the provided repository.
@@ -21,6 +34,24 @@ func NewHandler(repo Repository) *Handler {
 	}
 }
 
+// SetJobService enables async mode on routes that support it. Without a job
+// service, those routes always run synchronously regardless of SetDefaultAsync.
+func (h *Handler) SetJobService(jobService *job.Service) {
+	h.jobService = jobService
+}
+
+// SetDefaultAsync controls whether supported routes run as background jobs
+// by default (see job.WantsAsync for the per-request override rules).
+func (h *Handler) SetDefaultAsync(defaultAsync bool) {
+	h.defaultAsync = defaultAsync
+}
+
+// SetSlugIndex enables the shared slug-uniqueness index for slug generation.
+// Without an index, CreateJobCategory falls back to scanning repo.List.
+func (h *Handler) SetSlugIndex(idx *slugindex.Index) {
+	h.slugIndex = idx
+}
+
 // RegisterRoutes attaches job category routes to the supplied router group.
 func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	router.GET("/job-categories", h.ListJobCategories)
@@ -39,7 +70,7 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 // @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
 // @Router       /job-categories [get]
 func (h *Handler) ListJobCategories(c *gin.Context) {
-	jobCategories := h.repo.List()
+	jobCategories := h.repo.List(c.Request.Context())
 	response.Success(c, http.StatusOK, jobCategories, "Job categories retrieved successfully")
 }
 
@@ -73,35 +104,67 @@ func (h *Handler) CreateJobCategory(c *gin.Context) {
 		jobCategorySlug = slug.Make(payload.Name)
 	}
 
-	jobCategorySlug = ensureUniqueSlug(h.repo, jobCategorySlug)
+	var release slugindex.ReleaseFunc
+	if h.slugIndex != nil {
+		jobCategorySlug, release = h.slugIndex.Reserve("jobcategory", jobCategorySlug)
+		defer release()
+	} else {
+		jobCategorySlug = ensureUniqueSlug(c.Request.Context(), h.repo, jobCategorySlug)
+	}
 
 	jobCategory := JobCategory{
 		Name: payload.Name,
 		Slug: jobCategorySlug,
 	}
 
+	if h.jobService != nil && job.WantsAsync(c, h.defaultAsync) {
+		h.createAsync(c, jobCategory)
+		return
+	}
+
 	// Create in repository (repository handles Airtable sync if configured)
 	created, err := h.repo.Create(c.Request.Context(), jobCategory)
 	if err != nil {
-		response.InternalError(c, "Failed to create job category: "+err.Error())
+		response.WriteError(c, http.StatusInternalServerError, response.ErrCodeInternal, "Failed to create job category", err, nil)
 		return
 	}
 
 	response.Success(c, http.StatusCreated, created, "Job category created successfully")
 }
 
+// createAsync submits the create as a background job and responds 202 with
+// a Location header pointing at the job status endpoint.
+func (h *Handler) createAsync(c *gin.Context, jobCategory JobCategory) {
+	resourceGUID, err := job.NewResourceGUID()
+	if err != nil {
+		response.WriteError(c, http.StatusInternalServerError, response.ErrCodeInternal, "Failed to schedule job category creation", err, nil)
+		return
+	}
+
+	guid, err := h.jobService.Submit(job.TypeJobCategoryWrite, resourceGUID, func(ctx context.Context) (interface{}, error) {
+		return h.repo.Create(ctx, jobCategory)
+	})
+	if err != nil {
+		response.WriteError(c, http.StatusInternalServerError, response.ErrCodeInternal, "Failed to schedule job category creation", err, nil)
+		return
+	}
+
+	c.Header("Location", "/api/jobs/"+guid)
+	response.Success(c, http.StatusAccepted, gin.H{"job_guid": guid}, "Job category creation scheduled")
+}
+
 type jobCategoryPayload struct {
 	Name string `json:"name" binding:"required"` // Required
 	Slug string `json:"slug"`                     // Optional, will be generated from name if not provided
 }
 
-func ensureUniqueSlug(repo Repository, baseSlug string) string {
+func ensureUniqueSlug(ctx context.Context, repo Repository, baseSlug string) string {
 	if baseSlug == "" {
 		baseSlug = "job-category"
 	}
 
 	existingSlugs := make(map[string]struct{})
-	for _, jc := range repo.List() {
+	for _, jc := range repo.List(ctx) {
 		existingSlugs[jc.Slug] = struct{}{}
 	}
 
@@ -143,7 +206,23 @@ func (h *Handler) DeleteJobCategoryBySlug(c *gin.Context) {
 		return
 	}
 
-	if ok := h.repo.DeleteBySlug(normalizedSlug); !ok {
+	if h.jobService != nil && job.WantsAsync(c, h.defaultAsync) {
+		guid, err := h.jobService.Submit(job.TypeJobCategoryWrite, normalizedSlug, func(ctx context.Context) (interface{}, error) {
+			if ok := h.repo.DeleteBySlug(ctx, normalizedSlug); !ok {
+				return nil, fmt.Errorf("job category %q not found", normalizedSlug)
+			}
+			return gin.H{"slug": normalizedSlug}, nil
+		})
+		if err != nil {
+			response.WriteError(c, http.StatusInternalServerError, response.ErrCodeInternal, "Failed to schedule job category deletion", err, nil)
+			return
+		}
+		c.Header("Location", "/api/jobs/"+guid)
+		response.Success(c, http.StatusAccepted, gin.H{"job_guid": guid}, "Job category deletion scheduled")
+		return
+	}
+
+	if ok := h.repo.DeleteBySlug(c.Request.Context(), normalizedSlug); !ok {
 		response.NotFound(c, "Job category")
 		return
 	}
@@ -2,98 +2,124 @@ package jobcategory
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"strings"
 
 	"lam-phuong-api/internal/airtable"
+	"lam-phuong-api/internal/airtable/repo"
+	"lam-phuong-api/internal/logger"
+	"lam-phuong-api/internal/replication"
+	"lam-phuong-api/internal/slugindex"
 )
 
 // Repository defines behavior for storing and retrieving job categories.
 type Repository interface {
-	List() []JobCategory
+	List(ctx context.Context) []JobCategory
 	Create(ctx context.Context, jobCategory JobCategory) (JobCategory, error)
-	DeleteBySlug(slug string) bool
+	DeleteBySlug(ctx context.Context, slug string) bool
 }
 
-// AirtableRepository implements Repository interface using Airtable as the data store
+// jobCategorySchema describes JobCategory to the generic Airtable repository.
+var jobCategorySchema = repo.Schema[JobCategory]{
+	SlugField:      FieldSlug,
+	FromRecord:     mapAirtableRecord,
+	ToCreateFields: func(jc JobCategory) map[string]interface{} { return jc.ToAirtableFieldsForCreate() },
+	ToUpdateFields: func(jc JobCategory) map[string]interface{} { return jc.ToAirtableFieldsForUpdate() },
+	SetID:          func(jc *JobCategory, id string) { jc.ID = id },
+}
+
+// AirtableRepository implements Repository as a thin wrapper over the
+// generic repo.Repository[JobCategory].
 type AirtableRepository struct {
-	airtableClient *airtable.Client
-	airtableTable  string
+	repo       *repo.Repository[JobCategory]
+	replicator replication.Emitter
+	slugIndex  *slugindex.Index
 }
 
 // NewAirtableRepository creates a repository that uses Airtable as the data store
 func NewAirtableRepository(airtableClient *airtable.Client, airtableTable string) *AirtableRepository {
 	return &AirtableRepository{
-		airtableClient: airtableClient,
-		airtableTable:  airtableTable,
+		repo: repo.New(airtableClient, airtableTable, jobCategorySchema),
 	}
 }
 
-// List returns all job categories from Airtable
-func (r *AirtableRepository) List() []JobCategory {
-	records, err := r.airtableClient.ListRecords(context.Background(), r.airtableTable, nil)
-	if err != nil {
-		log.Printf("Failed to list job categories from Airtable: %v", err)
-		return []JobCategory{} // Return empty slice on error
+// SetReplicator enables mirroring Create/DeleteBySlug mutations to any
+// replication policy registered for this table. Without a replicator, those
+// methods still work but replicate nowhere.
+func (r *AirtableRepository) SetReplicator(emitter replication.Emitter) {
+	r.replicator = emitter
+}
+
+// SetSlugIndex enables the shared slug-uniqueness index: Create/DeleteBySlug
+// write through to it so Reserve never needs a fresh List call. Without an
+// index, callers must fall back to scanning List themselves.
+func (r *AirtableRepository) SetSlugIndex(idx *slugindex.Index) {
+	r.slugIndex = idx
+}
+
+// emitReplication queues a replication event if a replicator has been
+// configured, logging (but not failing the calling operation on) any error.
+func (r *AirtableRepository) emitReplication(ctx context.Context, action replication.Action, resourceID string, fields map[string]interface{}) {
+	if r.replicator == nil {
+		return
+	}
+
+	event := replication.Event{
+		Table:      "jobcategory",
+		Action:     action,
+		ResourceID: resourceID,
+		Fields:     fields,
 	}
 
-	jobCategories := make([]JobCategory, 0, len(records))
-	for _, record := range records {
-		jc, err := mapAirtableRecord(record)
-		if err != nil {
-			log.Printf("Skipping Airtable record due to mapping error: %v", err)
-			continue
-		}
-		jobCategories = append(jobCategories, jc)
+	if err := r.replicator.Emit(ctx, event); err != nil {
+		log := logger.FromContext(ctx)
+		log.Warn().Err(err).Str("id", resourceID).Msg("failed to emit job category replication event")
 	}
+}
 
+// List returns all job categories from Airtable
+func (r *AirtableRepository) List(ctx context.Context) []JobCategory {
+	jobCategories, err := r.repo.List(ctx)
+	if err != nil {
+		log := logger.FromContext(ctx)
+		log.Error().Err(err).Msg("failed to list job categories from Airtable")
+		return []JobCategory{} // Return empty slice on error
+	}
 	return jobCategories
 }
 
 // Create adds a new job category to Airtable
 func (r *AirtableRepository) Create(ctx context.Context, jobCategory JobCategory) (JobCategory, error) {
-	// Save to Airtable
-	airtableFields := jobCategory.ToAirtableFieldsForCreate()
-	log.Printf("Attempting to save job category to Airtable table: %s", r.airtableTable)
-	airtableRecord, err := r.airtableClient.CreateRecord(ctx, r.airtableTable, airtableFields)
+	created, err := r.repo.Create(ctx, jobCategory)
 	if err != nil {
-		log.Printf("Failed to save job category to Airtable: %v", err)
-		log.Printf("Error details - Table: %s, Fields: %+v", r.airtableTable, airtableFields)
-		return JobCategory{}, fmt.Errorf("failed to create job category in Airtable: %w", err)
+		return JobCategory{}, err
 	}
 
-	// Update the created job category with Airtable ID
-	jobCategory.ID = airtableRecord.ID
-	log.Printf("Job category saved to Airtable successfully with ID: %s", airtableRecord.ID)
-	return jobCategory, nil
+	if r.slugIndex != nil {
+		r.slugIndex.Set("jobcategory", created.Slug, created.ID)
+	}
+
+	r.emitReplication(ctx, replication.ActionCreate, created.ID, created.ToAirtableFieldsForCreate())
+
+	return created, nil
 }
 
 // DeleteBySlug removes a job category by its slug from Airtable
-func (r *AirtableRepository) DeleteBySlug(slug string) bool {
-	filterValue := escapeAirtableFormulaValue(slug)
-	params := &airtable.ListParams{
-		FilterByFormula: fmt.Sprintf("{%s} = '%s'", FieldSlug, filterValue),
-	}
-
-	records, err := r.airtableClient.ListRecords(context.Background(), r.airtableTable, params)
+func (r *AirtableRepository) DeleteBySlug(ctx context.Context, slug string) bool {
+	ids, err := r.repo.DeleteBySlug(ctx, slug)
 	if err != nil {
-		log.Printf("Failed to query Airtable for slug %s: %v", slug, err)
+		log := logger.FromContext(ctx)
+		log.Error().Err(err).Str("slug", slug).Msg("failed to delete job categories for slug")
 		return false
 	}
-
-	if len(records) == 0 {
+	if len(ids) == 0 {
 		return false
 	}
 
-	ids := make([]string, 0, len(records))
-	for _, record := range records {
-		ids = append(ids, record.ID)
+	if r.slugIndex != nil {
+		r.slugIndex.Delete("jobcategory", slug)
 	}
 
-	if err := r.airtableClient.BulkDeleteRecords(context.Background(), r.airtableTable, ids); err != nil {
-		log.Printf("Failed to delete Airtable records for slug %s: %v", slug, err)
-		return false
+	for _, id := range ids {
+		r.emitReplication(ctx, replication.ActionDelete, id, nil)
 	}
 
 	return true
@@ -106,8 +132,3 @@ func mapAirtableRecord(record airtable.Record) (JobCategory, error) {
 		Slug: getStringField(record.Fields, FieldSlug),
 	}, nil
 }
-
-func escapeAirtableFormulaValue(value string) string {
-	return strings.ReplaceAll(value, "'", "''")
-}
-
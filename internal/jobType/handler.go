@@ -1,17 +1,30 @@
 package jobtype
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gosimple/slug"
+	"lam-phuong-api/internal/job"
 	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/slugindex"
 )
 
+func init() {
+	job.RegisterPresenter(job.TypeJobTypeWrite, func(j job.Job) interface{} {
+		return j.Result
+	})
+	job.RegisterResourcePath(job.TypeJobTypeWrite, "/api/job-types")
+}
+
 // Handler exposes HTTP handlers for the job type resource.
 type Handler struct {
-	repo Repository
+	repo         Repository
+	jobService   *job.Service
+	defaultAsync bool
+	slugIndex    *slugindex.Index
 }
 
 // NewHandler creates a handler with the provided repository.
@@ -21,11 +34,30 @@ func NewHandler(repo Repository) *Handler {
 	}
 }
 
+// SetJobService enables async mode on routes that support it. Without a job
+// service, those routes always run synchronously regardless of SetDefaultAsync.
+func (h *Handler) SetJobService(jobService *job.Service) {
+	h.jobService = jobService
+}
+
+// SetDefaultAsync controls whether supported routes run as background jobs
+// by default (see job.WantsAsync for the per-request override rules).
+func (h *Handler) SetDefaultAsync(defaultAsync bool) {
+	h.defaultAsync = defaultAsync
+}
+
+// SetSlugIndex enables the shared slug-uniqueness index for slug generation.
+// Without an index, CreateJobType falls back to scanning repo.List.
+func (h *Handler) SetSlugIndex(idx *slugindex.Index) {
+	h.slugIndex = idx
+}
+
 // RegisterRoutes attaches job type routes to the supplied router group.
 func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	router.GET("/job-types", h.ListJobTypes)
 	router.POST("/job-types", h.CreateJobType)
 	router.DELETE("/job-types/:slug", h.DeleteJobTypeBySlug)
+	router.POST("/job-types/import", h.ImportJobTypes)
 }
 
 // ListJobTypes godoc
@@ -39,7 +71,7 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 // @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
 // @Router       /job-types [get]
 func (h *Handler) ListJobTypes(c *gin.Context) {
-	jobTypes := h.repo.List()
+	jobTypes := h.repo.List(c.Request.Context())
 	response.Success(c, http.StatusOK, jobTypes, "Job types retrieved successfully")
 }
 
@@ -73,35 +105,137 @@ func (h *Handler) CreateJobType(c *gin.Context) {
 		jobTypeSlug = slug.Make(payload.Name)
 	}
 
-	jobTypeSlug = ensureUniqueSlug(h.repo, jobTypeSlug)
+	var release slugindex.ReleaseFunc
+	if h.slugIndex != nil {
+		jobTypeSlug, release = h.slugIndex.Reserve("jobtype", jobTypeSlug)
+		defer release()
+	} else {
+		jobTypeSlug = ensureUniqueSlug(c.Request.Context(), h.repo, jobTypeSlug)
+	}
 
 	jobType := JobType{
 		Name: payload.Name,
 		Slug: jobTypeSlug,
 	}
 
+	if h.jobService != nil && job.WantsAsync(c, h.defaultAsync) {
+		h.createAsync(c, jobType)
+		return
+	}
+
 	// Create in repository (repository handles Airtable sync if configured)
 	created, err := h.repo.Create(c.Request.Context(), jobType)
 	if err != nil {
-		response.InternalError(c, "Failed to create job type: "+err.Error())
+		response.WriteError(c, http.StatusInternalServerError, response.ErrCodeInternal, "Failed to create job type", err, nil)
 		return
 	}
 
 	response.Success(c, http.StatusCreated, created, "Job type created successfully")
 }
 
+// createAsync submits the create as a background job and responds 202 with
+// a Location header pointing at the job status endpoint.
+func (h *Handler) createAsync(c *gin.Context, jobType JobType) {
+	resourceGUID, err := job.NewResourceGUID()
+	if err != nil {
+		response.WriteError(c, http.StatusInternalServerError, response.ErrCodeInternal, "Failed to schedule job type creation", err, nil)
+		return
+	}
+
+	guid, err := h.jobService.Submit(job.TypeJobTypeWrite, resourceGUID, func(ctx context.Context) (interface{}, error) {
+		return h.repo.Create(ctx, jobType)
+	})
+	if err != nil {
+		response.WriteError(c, http.StatusInternalServerError, response.ErrCodeInternal, "Failed to schedule job type creation", err, nil)
+		return
+	}
+
+	c.Header("Location", "/api/jobs/"+guid)
+	response.Success(c, http.StatusAccepted, gin.H{"job_guid": guid}, "Job type creation scheduled")
+}
+
+// ImportJobTypes godoc
+// @Summary      Bulk import job types from CSV
+// @Description  Upload a CSV file (header row "name,slug") and create a job type per row in the background (requires authentication)
+// @Tags         job-types
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file  formData  file  true  "CSV file with a name,slug header row"
+// @Success      202   {object}  response.Response  "Import scheduled"
+// @Failure      400   {object}  response.ErrorResponse  "Validation error"
+// @Failure      401   {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      503   {object}  response.ErrorResponse  "Job service unavailable"
+// @Router       /job-types/import [post]
+func (h *Handler) ImportJobTypes(c *gin.Context) {
+	if h.jobService == nil {
+		response.Error(c, http.StatusServiceUnavailable, response.ErrCodeInternal, "Job service is not configured", nil)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "CSV file is required", nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.InternalError(c, "Failed to read uploaded file: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	resourceGUID, err := job.NewResourceGUID()
+	if err != nil {
+		response.InternalError(c, "Failed to schedule job type import: "+err.Error())
+		return
+	}
+
+	guid, err := job.SubmitCSVImport(h.jobService, job.TypeBulkImportJobTypes, resourceGUID, file, parseJobTypeRow, h.repo.Create)
+	if err != nil {
+		response.ValidationError(c, "Invalid CSV file", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Location", "/api/jobs/"+guid)
+	response.Success(c, http.StatusAccepted, gin.H{"job_guid": guid}, "Job type import scheduled")
+}
+
+// parseJobTypeRow maps a "name,slug" CSV row to a JobType, generating the
+// slug from the name when the slug column is blank.
+func parseJobTypeRow(row []string) (JobType, error) {
+	if len(row) < 1 || row[0] == "" {
+		return JobType{}, fmt.Errorf("row is missing a name: %v", row)
+	}
+
+	jobTypeSlug := ""
+	if len(row) > 1 {
+		jobTypeSlug = row[1]
+	}
+	if jobTypeSlug != "" {
+		jobTypeSlug = slug.Make(jobTypeSlug)
+	} else {
+		jobTypeSlug = slug.Make(row[0])
+	}
+
+	return JobType{Name: row[0], Slug: jobTypeSlug}, nil
+}
+
 type jobTypePayload struct {
 	Name string `json:"name" binding:"required"` // Required
 	Slug string `json:"slug"`                     // Optional, will be generated from name if not provided
 }
 
-func ensureUniqueSlug(repo Repository, baseSlug string) string {
+func ensureUniqueSlug(ctx context.Context, repo Repository, baseSlug string) string {
 	if baseSlug == "" {
 		baseSlug = "job-type"
 	}
 
 	existingSlugs := make(map[string]struct{})
-	for _, jt := range repo.List() {
+	for _, jt := range repo.List(ctx) {
 		existingSlugs[jt.Slug] = struct{}{}
 	}
 
@@ -143,7 +277,23 @@ func (h *Handler) DeleteJobTypeBySlug(c *gin.Context) {
 		return
 	}
 
-	if ok := h.repo.DeleteBySlug(normalizedSlug); !ok {
+	if h.jobService != nil && job.WantsAsync(c, h.defaultAsync) {
+		guid, err := h.jobService.Submit(job.TypeJobTypeWrite, normalizedSlug, func(ctx context.Context) (interface{}, error) {
+			if ok := h.repo.DeleteBySlug(ctx, normalizedSlug); !ok {
+				return nil, fmt.Errorf("job type %q not found", normalizedSlug)
+			}
+			return gin.H{"slug": normalizedSlug}, nil
+		})
+		if err != nil {
+			response.WriteError(c, http.StatusInternalServerError, response.ErrCodeInternal, "Failed to schedule job type deletion", err, nil)
+			return
+		}
+		c.Header("Location", "/api/jobs/"+guid)
+		response.Success(c, http.StatusAccepted, gin.H{"job_guid": guid}, "Job type deletion scheduled")
+		return
+	}
+
+	if ok := h.repo.DeleteBySlug(c.Request.Context(), normalizedSlug); !ok {
 		response.NotFound(c, "Job type")
 		return
 	}
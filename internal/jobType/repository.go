@@ -2,98 +2,160 @@ package jobtype
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"strings"
 
 	"lam-phuong-api/internal/airtable"
+	"lam-phuong-api/internal/airtable/repo"
+	"lam-phuong-api/internal/audit"
+	"lam-phuong-api/internal/logger"
+	"lam-phuong-api/internal/replication"
+	"lam-phuong-api/internal/slugindex"
 )
 
 // Repository defines behavior for storing and retrieving job types.
 type Repository interface {
-	List() []JobType
+	List(ctx context.Context) []JobType
 	Create(ctx context.Context, jobType JobType) (JobType, error)
-	DeleteBySlug(slug string) bool
+	DeleteBySlug(ctx context.Context, slug string) bool
 }
 
-// AirtableRepository implements Repository interface using Airtable as the data store
+// jobTypeSchema describes JobType to the generic Airtable repository.
+var jobTypeSchema = repo.Schema[JobType]{
+	SlugField:      FieldSlug,
+	FromRecord:     mapAirtableRecord,
+	ToCreateFields: func(jt JobType) map[string]interface{} { return jt.ToAirtableFieldsForCreate() },
+	ToUpdateFields: func(jt JobType) map[string]interface{} { return jt.ToAirtableFieldsForUpdate() },
+	SetID:          func(jt *JobType, id string) { jt.ID = id },
+}
+
+// AirtableRepository implements Repository as a thin wrapper over the
+// generic repo.Repository[JobType], adding only the audit logging this
+// domain wants on top of the shared CRUD plumbing.
 type AirtableRepository struct {
-	airtableClient *airtable.Client
-	airtableTable  string
+	repo          *repo.Repository[JobType]
+	auditRecorder audit.Recorder
+	replicator    replication.Emitter
+	slugIndex     *slugindex.Index
 }
 
 // NewAirtableRepository creates a repository that uses Airtable as the data store
 func NewAirtableRepository(airtableClient *airtable.Client, airtableTable string) *AirtableRepository {
 	return &AirtableRepository{
-		airtableClient: airtableClient,
-		airtableTable:  airtableTable,
+		repo: repo.New(airtableClient, airtableTable, jobTypeSchema),
 	}
 }
 
-// List returns all job types from Airtable
-func (r *AirtableRepository) List() []JobType {
-	records, err := r.airtableClient.ListRecords(context.Background(), r.airtableTable, nil)
-	if err != nil {
-		log.Printf("Failed to list job types from Airtable: %v", err)
-		return []JobType{} // Return empty slice on error
+// SetAuditRecorder enables field-level audit logging on Create/DeleteBySlug.
+func (r *AirtableRepository) SetAuditRecorder(recorder audit.Recorder) {
+	r.auditRecorder = recorder
+}
+
+// SetReplicator enables mirroring Create/DeleteBySlug mutations to any
+// replication policy registered for the "jobtype" table.
+// Without a replicator, those methods still work but replicate nowhere.
+func (r *AirtableRepository) SetReplicator(emitter replication.Emitter) {
+	r.replicator = emitter
+}
+
+// SetSlugIndex enables the shared slug-uniqueness index: Create/DeleteBySlug
+// write through to it so Reserve never needs a fresh List call. Without an
+// index, callers must fall back to scanning List themselves.
+func (r *AirtableRepository) SetSlugIndex(idx *slugindex.Index) {
+	r.slugIndex = idx
+}
+
+// emitReplication queues a replication event if a replicator has been
+// configured, logging (but not failing the calling operation on) any error.
+func (r *AirtableRepository) emitReplication(ctx context.Context, action replication.Action, resourceID string, fields map[string]interface{}) {
+	if r.replicator == nil {
+		return
+	}
+
+	event := replication.Event{
+		Table:      "jobtype",
+		Action:     action,
+		ResourceID: resourceID,
+		Fields:     fields,
+	}
+
+	if err := r.replicator.Emit(ctx, event); err != nil {
+		log := logger.FromContext(ctx)
+		log.Warn().Err(err).Str("id", resourceID).Msg("failed to emit job type replication event")
+	}
+}
+
+// recordAudit writes an audit entry if a recorder has been configured,
+// logging (but not failing the calling operation on) any write error.
+func (r *AirtableRepository) recordAudit(ctx context.Context, action audit.Action, resourceID string, changes []audit.FieldChange) {
+	if r.auditRecorder == nil {
+		return
 	}
 
-	jobTypes := make([]JobType, 0, len(records))
-	for _, record := range records {
-		jt, err := mapAirtableRecord(record)
-		if err != nil {
-			log.Printf("Skipping Airtable record due to mapping error: %v", err)
-			continue
-		}
-		jobTypes = append(jobTypes, jt)
+	entry := audit.Entry{
+		Actor:        audit.ActorFromContext(ctx),
+		Action:       action,
+		ResourceType: "jobtype",
+		ResourceID:   resourceID,
+		Changes:      changes,
+		RequestID:    audit.RequestIDFromContext(ctx),
 	}
 
+	if err := r.auditRecorder.Record(ctx, entry); err != nil {
+		log := logger.FromContext(ctx)
+		log.Warn().Err(err).Str("id", resourceID).Msg("failed to record job type audit entry")
+	}
+}
+
+// List returns all job types from Airtable
+func (r *AirtableRepository) List(ctx context.Context) []JobType {
+	jobTypes, err := r.repo.List(ctx)
+	if err != nil {
+		log := logger.FromContext(ctx)
+		log.Error().Err(err).Msg("failed to list job types from Airtable")
+		return []JobType{} // Return empty slice on error
+	}
 	return jobTypes
 }
 
 // Create adds a new job type to Airtable
 func (r *AirtableRepository) Create(ctx context.Context, jobType JobType) (JobType, error) {
-	// Save to Airtable
-	airtableFields := jobType.ToAirtableFieldsForCreate()
-	log.Printf("Attempting to save job type to Airtable table: %s", r.airtableTable)
-	airtableRecord, err := r.airtableClient.CreateRecord(ctx, r.airtableTable, airtableFields)
+	fields := jobType.ToAirtableFieldsForCreate()
+
+	created, err := r.repo.Create(ctx, jobType)
 	if err != nil {
-		log.Printf("Failed to save job type to Airtable: %v", err)
-		log.Printf("Error details - Table: %s, Fields: %+v", r.airtableTable, airtableFields)
-		return JobType{}, fmt.Errorf("failed to create job type in Airtable: %w", err)
+		return JobType{}, err
+	}
+
+	if r.slugIndex != nil {
+		r.slugIndex.Set("jobtype", created.Slug, created.ID)
 	}
 
-	// Update the created job type with Airtable ID
-	jobType.ID = airtableRecord.ID
-	log.Printf("Job type saved to Airtable successfully with ID: %s", airtableRecord.ID)
-	return jobType, nil
+	r.recordAudit(ctx, audit.ActionCreate, created.ID, audit.DiffFields(nil, fields))
+	r.emitReplication(ctx, replication.ActionCreate, created.ID, fields)
+
+	return created, nil
 }
 
 // DeleteBySlug removes a job type by its slug from Airtable
-func (r *AirtableRepository) DeleteBySlug(slug string) bool {
-	filterValue := escapeAirtableFormulaValue(slug)
-	params := &airtable.ListParams{
-		FilterByFormula: fmt.Sprintf("{%s} = '%s'", FieldSlug, filterValue),
-	}
-
-	records, err := r.airtableClient.ListRecords(context.Background(), r.airtableTable, params)
+func (r *AirtableRepository) DeleteBySlug(ctx context.Context, slug string) bool {
+	ids, err := r.repo.DeleteBySlug(ctx, slug)
 	if err != nil {
-		log.Printf("Failed to query Airtable for slug %s: %v", slug, err)
+		log := logger.FromContext(ctx)
+		log.Error().Err(err).Str("slug", slug).Msg("failed to delete job types for slug")
 		return false
 	}
-
-	if len(records) == 0 {
+	if len(ids) == 0 {
 		return false
 	}
 
-	ids := make([]string, 0, len(records))
-	for _, record := range records {
-		ids = append(ids, record.ID)
+	if r.slugIndex != nil {
+		r.slugIndex.Delete("jobtype", slug)
 	}
 
-	if err := r.airtableClient.BulkDeleteRecords(context.Background(), r.airtableTable, ids); err != nil {
-		log.Printf("Failed to delete Airtable records for slug %s: %v", slug, err)
-		return false
+	// One audit entry and one replication event per deleted record, each
+	// attributed to the actor and request ID carried in ctx.
+	for _, id := range ids {
+		r.recordAudit(ctx, audit.ActionDelete, id, nil)
+		r.emitReplication(ctx, replication.ActionDelete, id, nil)
 	}
 
 	return true
@@ -106,8 +168,3 @@ func mapAirtableRecord(record airtable.Record) (JobType, error) {
 		Slug: getStringField(record.Fields, FieldSlug),
 	}, nil
 }
-
-func escapeAirtableFormulaValue(value string) string {
-	return strings.ReplaceAll(value, "'", "''")
-}
-
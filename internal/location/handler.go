@@ -1,17 +1,30 @@
 package location
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gosimple/slug"
+	"lam-phuong-api/internal/job"
 	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/slugindex"
 )
 
+func init() {
+	job.RegisterPresenter(job.TypeLocationWrite, func(j job.Job) interface{} {
+		return j.Result
+	})
+	job.RegisterResourcePath(job.TypeLocationWrite, "/api/locations")
+}
+
 // Handler exposes HTTP handlers for the location resource.
 type Handler struct {
-	repo Repository
+	repo         Repository
+	jobService   *job.Service
+	defaultAsync bool
+	slugIndex    *slugindex.Index
 }
 
 // NewHandler creates a handler with the provided repository.
@@ -21,11 +34,30 @@ func NewHandler(repo Repository) *Handler {
 	}
 }
 
+// SetJobService enables async mode on routes that support it. Without a job
+// service, those routes always run synchronously regardless of SetDefaultAsync.
+func (h *Handler) SetJobService(jobService *job.Service) {
+	h.jobService = jobService
+}
+
+// SetDefaultAsync controls whether supported routes run as background jobs
+// by default (see job.WantsAsync for the per-request override rules).
+func (h *Handler) SetDefaultAsync(defaultAsync bool) {
+	h.defaultAsync = defaultAsync
+}
+
+// SetSlugIndex enables the shared slug-uniqueness index for slug generation.
+// Without an index, CreateLocation falls back to scanning repo.List.
+func (h *Handler) SetSlugIndex(idx *slugindex.Index) {
+	h.slugIndex = idx
+}
+
 // RegisterRoutes attaches location routes to the supplied router group.
 func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	router.GET("/locations", h.ListLocations)
 	router.POST("/locations", h.CreateLocation)
 	router.DELETE("/locations/:slug", h.DeleteLocationBySlug)
+	router.POST("/locations/import", h.ImportLocations)
 }
 
 // ListLocations godoc
@@ -39,7 +71,7 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 // @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
 // @Router       /locations [get]
 func (h *Handler) ListLocations(c *gin.Context) {
-	locations := h.repo.List()
+	locations := h.repo.List(c.Request.Context())
 	response.Success(c, http.StatusOK, locations, "Locations retrieved successfully")
 }
 
@@ -73,13 +105,24 @@ func (h *Handler) CreateLocation(c *gin.Context) {
 		locationSlug = slug.Make(payload.Name)
 	}
 
-	locationSlug = ensureUniqueSlug(h.repo, locationSlug)
+	var release slugindex.ReleaseFunc
+	if h.slugIndex != nil {
+		locationSlug, release = h.slugIndex.Reserve("location", locationSlug)
+		defer release()
+	} else {
+		locationSlug = ensureUniqueSlug(c.Request.Context(), h.repo, locationSlug)
+	}
 
 	location := Location{
 		Name: payload.Name,
 		Slug: locationSlug,
 	}
 
+	if h.jobService != nil && job.WantsAsync(c, h.defaultAsync) {
+		h.createAsync(c, location)
+		return
+	}
+
 	// Create in repository (repository handles Airtable sync if configured)
 	created, err := h.repo.Create(c.Request.Context(), location)
 	if err != nil {
@@ -90,18 +133,109 @@ func (h *Handler) CreateLocation(c *gin.Context) {
 	response.Success(c, http.StatusCreated, created, "Location created successfully")
 }
 
+// createAsync submits the create as a background job and responds 202 with
+// a Location header pointing at the job status endpoint.
+func (h *Handler) createAsync(c *gin.Context, location Location) {
+	resourceGUID, err := job.NewResourceGUID()
+	if err != nil {
+		response.InternalError(c, "Failed to schedule location creation: "+err.Error())
+		return
+	}
+
+	guid, err := h.jobService.Submit(job.TypeLocationWrite, resourceGUID, func(ctx context.Context) (interface{}, error) {
+		return h.repo.Create(ctx, location)
+	})
+	if err != nil {
+		response.InternalError(c, "Failed to schedule location creation: "+err.Error())
+		return
+	}
+
+	c.Header("Location", "/api/jobs/"+guid)
+	response.Success(c, http.StatusAccepted, gin.H{"job_guid": guid}, "Location creation scheduled")
+}
+
+// ImportLocations godoc
+// @Summary      Bulk import locations from CSV
+// @Description  Upload a CSV file (header row "name,slug") and create a location per row in the background (requires authentication)
+// @Tags         locations
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file  formData  file  true  "CSV file with a name,slug header row"
+// @Success      202   {object}  response.Response  "Import scheduled"
+// @Failure      400   {object}  response.ErrorResponse  "Validation error"
+// @Failure      401   {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      503   {object}  response.ErrorResponse  "Job service unavailable"
+// @Router       /locations/import [post]
+func (h *Handler) ImportLocations(c *gin.Context) {
+	if h.jobService == nil {
+		response.Error(c, http.StatusServiceUnavailable, response.ErrCodeInternal, "Job service is not configured", nil)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "CSV file is required", nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.InternalError(c, "Failed to read uploaded file: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	resourceGUID, err := job.NewResourceGUID()
+	if err != nil {
+		response.InternalError(c, "Failed to schedule location import: "+err.Error())
+		return
+	}
+
+	guid, err := job.SubmitCSVImport(h.jobService, job.TypeBulkImportLocations, resourceGUID, file, parseLocationRow, h.repo.Create)
+	if err != nil {
+		response.ValidationError(c, "Invalid CSV file", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Location", "/api/jobs/"+guid)
+	response.Success(c, http.StatusAccepted, gin.H{"job_guid": guid}, "Location import scheduled")
+}
+
+// parseLocationRow maps a "name,slug" CSV row to a Location, generating the
+// slug from the name when the slug column is blank.
+func parseLocationRow(row []string) (Location, error) {
+	if len(row) < 1 || row[0] == "" {
+		return Location{}, fmt.Errorf("row is missing a name: %v", row)
+	}
+
+	locationSlug := ""
+	if len(row) > 1 {
+		locationSlug = row[1]
+	}
+	if locationSlug != "" {
+		locationSlug = slug.Make(locationSlug)
+	} else {
+		locationSlug = slug.Make(row[0])
+	}
+
+	return Location{Name: row[0], Slug: locationSlug}, nil
+}
+
 type locationPayload struct {
 	Name string `json:"name" binding:"required"` // Required
 	Slug string `json:"slug"`                    // Optional, will be generated from name if not provided
 }
 
-func ensureUniqueSlug(repo Repository, baseSlug string) string {
+func ensureUniqueSlug(ctx context.Context, repo Repository, baseSlug string) string {
 	if baseSlug == "" {
 		baseSlug = "location"
 	}
 
 	existingSlugs := make(map[string]struct{})
-	for _, loc := range repo.List() {
+	for _, loc := range repo.List(ctx) {
 		existingSlugs[loc.Slug] = struct{}{}
 	}
 
@@ -143,7 +277,23 @@ func (h *Handler) DeleteLocationBySlug(c *gin.Context) {
 		return
 	}
 
-	if ok := h.repo.DeleteBySlug(normalizedSlug); !ok {
+	if h.jobService != nil && job.WantsAsync(c, h.defaultAsync) {
+		guid, err := h.jobService.Submit(job.TypeLocationWrite, normalizedSlug, func(ctx context.Context) (interface{}, error) {
+			if ok := h.repo.DeleteBySlug(ctx, normalizedSlug); !ok {
+				return nil, fmt.Errorf("location %q not found", normalizedSlug)
+			}
+			return gin.H{"slug": normalizedSlug}, nil
+		})
+		if err != nil {
+			response.InternalError(c, "Failed to schedule location deletion: "+err.Error())
+			return
+		}
+		c.Header("Location", "/api/jobs/"+guid)
+		response.Success(c, http.StatusAccepted, gin.H{"job_guid": guid}, "Location deletion scheduled")
+		return
+	}
+
+	if ok := h.repo.DeleteBySlug(c.Request.Context(), normalizedSlug); !ok {
 		response.NotFound(c, "Location")
 		return
 	}
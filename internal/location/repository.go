@@ -2,53 +2,158 @@ package location
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
-	"strings"
 
 	"lam-phuong-api/internal/airtable"
+	"lam-phuong-api/internal/airtable/repo"
+	"lam-phuong-api/internal/audit"
+	"lam-phuong-api/internal/logger"
+	"lam-phuong-api/internal/replication"
+	"lam-phuong-api/internal/slugindex"
+	"lam-phuong-api/internal/sync"
 )
 
 // Repository defines behavior for storing and retrieving locations.
 type Repository interface {
-	List() []Location
+	List(ctx context.Context) []Location
 	Create(ctx context.Context, location Location) (Location, error)
-	Get(id string) (Location, bool)
-	GetBySlug(slug string) (Location, bool)
+	Get(ctx context.Context, id string) (Location, bool)
+	GetBySlug(ctx context.Context, slug string) (Location, bool)
 	Update(ctx context.Context, id string, location Location) (Location, error)
-	DeleteBySlug(slug string) bool
+	DeleteBySlug(ctx context.Context, slug string) bool
+	// Warmup populates the repository's cache before the server starts
+	// accepting traffic. Repositories without a cache treat it as a no-op.
+	Warmup(ctx context.Context) error
 }
 
-// AirtableRepository implements Repository interface using Airtable as the data store
+// locationSchema describes Location to the generic Airtable repository.
+var locationSchema = repo.Schema[Location]{
+	SlugField:      FieldSlug,
+	FromRecord:     mapAirtableRecord,
+	ToCreateFields: func(l Location) map[string]interface{} { return l.ToAirtableFieldsForCreate() },
+	ToUpdateFields: func(l Location) map[string]interface{} { return l.ToAirtableFieldsForUpdate() },
+	SetID:          func(l *Location, id string) { l.ID = id },
+}
+
+// AirtableRepository implements Repository interface using Airtable as the
+// data store, via the generic repo.Repository[Location] for raw CRUD, with
+// an in-memory read-through cache and audit logging layered on top.
 type AirtableRepository struct {
-	airtableClient *airtable.Client
-	airtableTable  string
+	repo          *repo.Repository[Location]
+	cache         *sync.Cache
+	auditRecorder audit.Recorder
+	replicator    replication.Emitter
+	slugIndex     *slugindex.Index
 }
 
 // NewAirtableRepository creates a repository that uses Airtable as the data store
 func NewAirtableRepository(airtableClient *airtable.Client, airtableTable string) *AirtableRepository {
 	return &AirtableRepository{
-		airtableClient: airtableClient,
-		airtableTable:  airtableTable,
+		repo:  repo.New(airtableClient, airtableTable, locationSchema),
+		cache: sync.NewCache(),
+	}
+}
+
+// SetAuditRecorder enables field-level audit logging on Create/Update/
+// DeleteBySlug. Without a recorder, those methods still work but emit no
+// audit trail of their own (the router's AuditMiddleware still records a
+// generic entry for the request).
+func (r *AirtableRepository) SetAuditRecorder(recorder audit.Recorder) {
+	r.auditRecorder = recorder
+}
+
+// SetReplicator enables mirroring Create/DeleteBySlug mutations to any
+// replication policy registered for this table; Update is not replicated.
+// Without a replicator, those methods still work but replicate nowhere.
+func (r *AirtableRepository) SetReplicator(emitter replication.Emitter) {
+	r.replicator = emitter
+}
+
+// SetSlugIndex enables the shared slug-uniqueness index: Create/DeleteBySlug
+// write through to it so Reserve never needs a fresh List call. Without an
+// index, callers must fall back to scanning List themselves.
+func (r *AirtableRepository) SetSlugIndex(idx *slugindex.Index) {
+	r.slugIndex = idx
+}
+
+// emitReplication queues a replication event if a replicator has been
+// configured, logging (but not failing the calling operation on) any error.
+func (r *AirtableRepository) emitReplication(ctx context.Context, action replication.Action, resourceID string, fields map[string]interface{}) {
+	if r.replicator == nil {
+		return
+	}
+
+	event := replication.Event{
+		Table:      "location",
+		Action:     action,
+		ResourceID: resourceID,
+		Fields:     fields,
+	}
+
+	if err := r.replicator.Emit(ctx, event); err != nil {
+		log := logger.FromContext(ctx)
+		log.Warn().Err(err).Str("id", resourceID).Msg("failed to emit location replication event")
 	}
 }
 
-// List returns all locations from Airtable
-func (r *AirtableRepository) List() []Location {
-	records, err := r.airtableClient.ListRecords(context.Background(), r.airtableTable, nil)
+// recordAudit writes an audit entry if a recorder has been configured,
+// logging (but not failing the calling operation on) any write error.
+func (r *AirtableRepository) recordAudit(ctx context.Context, action audit.Action, resourceID string, changes []audit.FieldChange) {
+	if r.auditRecorder == nil {
+		return
+	}
+
+	entry := audit.Entry{
+		Actor:        audit.ActorFromContext(ctx),
+		Action:       action,
+		ResourceType: "location",
+		ResourceID:   resourceID,
+		Changes:      changes,
+		RequestID:    audit.RequestIDFromContext(ctx),
+	}
+
+	if err := r.auditRecorder.Record(ctx, entry); err != nil {
+		log := logger.FromContext(ctx)
+		log.Warn().Err(err).Str("id", resourceID).Msg("failed to record location audit entry")
+	}
+}
+
+// Warmup refreshes the cache from Airtable. The sync.Runner also calls this
+// on the configured cron schedule; NewRouter's caller should call it once
+// before router.Run so the server never serves an empty cache.
+func (r *AirtableRepository) Warmup(ctx context.Context) error {
+	locations, err := r.repo.List(ctx)
 	if err != nil {
-		log.Printf("Failed to list locations from Airtable: %v", err)
-		return []Location{} // Return empty slice on error
+		return fmt.Errorf("failed to warm up location cache: %w", err)
 	}
 
-	locations := make([]Location, 0, len(records))
-	for _, record := range records {
-		loc, err := mapAirtableRecord(record)
-		if err != nil {
-			log.Printf("Skipping Airtable record due to mapping error: %v", err)
-			continue
+	fresh := make(map[string]interface{}, len(locations))
+	for _, loc := range locations {
+		fresh[loc.ID] = loc
+	}
+	r.cache.Replace(fresh)
+	return nil
+}
+
+// List returns all locations, reading through the cache when it has been
+// warmed up and falling back to a live Airtable call otherwise.
+func (r *AirtableRepository) List(ctx context.Context) []Location {
+	if r.cache.Len() > 0 {
+		cached := r.cache.All()
+		locations := make([]Location, 0, len(cached))
+		for _, record := range cached {
+			locations = append(locations, record.(Location))
 		}
-		locations = append(locations, loc)
+		return locations
+	}
+
+	log := logger.FromContext(ctx)
+
+	locations, err := r.repo.List(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list locations from Airtable")
+		return []Location{} // Return empty slice on error
 	}
 
 	return locations
@@ -56,89 +161,111 @@ func (r *AirtableRepository) List() []Location {
 
 // Create adds a new location to Airtable
 func (r *AirtableRepository) Create(ctx context.Context, location Location) (Location, error) {
-	// Save to Airtable
-	airtableFields := location.ToAirtableFieldsForCreate()
-	log.Printf("Attempting to save location to Airtable table: %s", r.airtableTable)
-	airtableRecord, err := r.airtableClient.CreateRecord(ctx, r.airtableTable, airtableFields)
+	log := logger.FromContext(ctx)
+
+	fields := location.ToAirtableFieldsForCreate()
+	log.Info().Msg("attempting to save location to Airtable")
+	created, err := r.repo.Create(ctx, location)
 	if err != nil {
-		log.Printf("Failed to save location to Airtable: %v", err)
-		log.Printf("Error details - Table: %s, Fields: %+v", r.airtableTable, airtableFields)
-		return Location{}, fmt.Errorf("failed to create location in Airtable: %w", err)
+		log.Error().Err(err).Interface("fields", fields).Msg("failed to save location to Airtable")
+		return Location{}, err
+	}
+	log.Info().Str("id", created.ID).Msg("location saved to Airtable successfully")
+
+	// Write-through: make the new location visible to List() immediately,
+	// then schedule a re-fetch to catch any server-side Airtable mutation
+	// (formulas, rollups) we wouldn't otherwise see.
+	r.cache.Set(created.ID, created)
+	go r.verifyAfterWrite(context.Background(), created.ID)
+
+	if r.slugIndex != nil {
+		r.slugIndex.Set("location", created.Slug, created.ID)
 	}
 
-	// Update the created location with Airtable ID
-	location.ID = airtableRecord.ID
-	log.Printf("Location saved to Airtable successfully with ID: %s", airtableRecord.ID)
-	return location, nil
+	r.recordAudit(ctx, audit.ActionCreate, created.ID, audit.DiffFields(nil, fields))
+	r.emitReplication(ctx, replication.ActionCreate, created.ID, fields)
+
+	return created, nil
 }
 
-// DeleteBySlug removes a location by its slug from Airtable
-func (r *AirtableRepository) DeleteBySlug(slug string) bool {
-	filterValue := escapeAirtableFormulaValue(slug)
-	params := &airtable.ListParams{
-		FilterByFormula: fmt.Sprintf("{%s} = '%s'", FieldSlug, filterValue),
+// verifyAfterWrite re-fetches a single record from Airtable to reconcile
+// the cache with anything Airtable computed server-side after a write. It
+// runs detached from the originating request, so it gets its own context
+// rather than the request-scoped logger.
+func (r *AirtableRepository) verifyAfterWrite(ctx context.Context, id string) {
+	log := logger.FromContext(ctx)
+
+	loc, err := r.repo.Get(ctx, id)
+	if err != nil {
+		log.Warn().Err(err).Str("id", id).Msg("failed to verify cached location after write")
+		return
 	}
+	r.cache.Set(id, loc)
+}
+
+// DeleteBySlug removes a location by its slug from Airtable
+func (r *AirtableRepository) DeleteBySlug(ctx context.Context, slug string) bool {
+	log := logger.FromContext(ctx)
 
-	records, err := r.airtableClient.ListRecords(context.Background(), r.airtableTable, params)
+	ids, err := r.repo.DeleteBySlug(ctx, slug)
 	if err != nil {
-		log.Printf("Failed to query Airtable for slug %s: %v", slug, err)
+		log.Error().Err(err).Str("slug", slug).Msg("failed to delete Airtable records for slug")
 		return false
 	}
-
-	if len(records) == 0 {
+	if len(ids) == 0 {
 		return false
 	}
 
-	ids := make([]string, 0, len(records))
-	for _, record := range records {
-		ids = append(ids, record.ID)
+	if r.slugIndex != nil {
+		r.slugIndex.Delete("location", slug)
 	}
 
-	if err := r.airtableClient.BulkDeleteRecords(context.Background(), r.airtableTable, ids); err != nil {
-		log.Printf("Failed to delete Airtable records for slug %s: %v", slug, err)
-		return false
+	for _, id := range ids {
+		r.cache.Delete(id)
+		r.recordAudit(ctx, audit.ActionDelete, id, nil)
+		r.emitReplication(ctx, replication.ActionDelete, id, nil)
 	}
 
 	return true
 }
 
-// Get retrieves a location by ID from Airtable
-func (r *AirtableRepository) Get(id string) (Location, bool) {
-	record, err := r.airtableClient.GetRecord(context.Background(), r.airtableTable, id)
-	if err != nil {
-		log.Printf("Failed to get location from Airtable: %v", err)
-		return Location{}, false
+// Get retrieves a location by ID, reading through the cache when warm.
+func (r *AirtableRepository) Get(ctx context.Context, id string) (Location, bool) {
+	if cached, ok := r.cache.Get(id); ok {
+		return cached.(Location), true
 	}
 
-	loc, err := mapAirtableRecord(record)
+	log := logger.FromContext(ctx)
+
+	loc, err := r.repo.Get(ctx, id)
 	if err != nil {
-		log.Printf("Failed to map Airtable record: %v", err)
+		if !errors.Is(err, repo.ErrNotFound) {
+			log.Error().Err(err).Str("id", id).Msg("failed to get location from Airtable")
+		}
 		return Location{}, false
 	}
 
 	return loc, true
 }
 
-// GetBySlug retrieves a location by slug from Airtable
-func (r *AirtableRepository) GetBySlug(slug string) (Location, bool) {
-	filterValue := escapeAirtableFormulaValue(slug)
-	params := &airtable.ListParams{
-		FilterByFormula: fmt.Sprintf("{%s} = '%s'", FieldSlug, filterValue),
-	}
-
-	records, err := r.airtableClient.ListRecords(context.Background(), r.airtableTable, params)
-	if err != nil {
-		log.Printf("Failed to query Airtable for slug %s: %v", slug, err)
+// GetBySlug retrieves a location by slug, reading through the cache when warm.
+func (r *AirtableRepository) GetBySlug(ctx context.Context, slug string) (Location, bool) {
+	if r.cache.Len() > 0 {
+		for _, record := range r.cache.All() {
+			if loc := record.(Location); loc.Slug == slug {
+				return loc, true
+			}
+		}
 		return Location{}, false
 	}
 
-	if len(records) == 0 {
-		return Location{}, false
-	}
+	log := logger.FromContext(ctx)
 
-	loc, err := mapAirtableRecord(records[0])
+	loc, err := r.repo.GetBySlug(ctx, slug)
 	if err != nil {
-		log.Printf("Failed to map Airtable record: %v", err)
+		if !errors.Is(err, repo.ErrNotFound) {
+			log.Error().Err(err).Str("slug", slug).Msg("failed to query Airtable for slug")
+		}
 		return Location{}, false
 	}
 
@@ -147,20 +274,27 @@ func (r *AirtableRepository) GetBySlug(slug string) (Location, bool) {
 
 // Update updates a location in Airtable
 func (r *AirtableRepository) Update(ctx context.Context, id string, location Location) (Location, error) {
-	airtableFields := location.ToAirtableFieldsForUpdate()
-	log.Printf("Attempting to update location in Airtable table: %s", r.airtableTable)
-	airtableRecord, err := r.airtableClient.UpdateRecordPartial(ctx, r.airtableTable, id, airtableFields)
-	if err != nil {
-		log.Printf("Failed to update location in Airtable: %v", err)
-		return Location{}, fmt.Errorf("failed to update location in Airtable: %w", err)
+	log := logger.FromContext(ctx)
+
+	var before map[string]interface{}
+	if existing, ok := r.Get(ctx, id); ok {
+		before = existing.ToAirtableFieldsForUpdate()
 	}
 
-	updated, err := mapAirtableRecord(airtableRecord)
+	fields := location.ToAirtableFieldsForUpdate()
+	log.Info().Str("id", id).Msg("attempting to update location in Airtable")
+	updated, err := r.repo.Update(ctx, id, location)
 	if err != nil {
-		return Location{}, fmt.Errorf("failed to map updated location: %w", err)
+		log.Error().Err(err).Str("id", id).Msg("failed to update location in Airtable")
+		return Location{}, err
 	}
+	log.Info().Str("id", id).Msg("location updated in Airtable successfully")
+
+	r.cache.Set(updated.ID, updated)
+	go r.verifyAfterWrite(context.Background(), updated.ID)
+
+	r.recordAudit(ctx, audit.ActionUpdate, updated.ID, audit.DiffFields(before, fields))
 
-	log.Printf("Location updated in Airtable successfully with ID: %s", id)
 	return updated, nil
 }
 
@@ -171,7 +305,3 @@ func mapAirtableRecord(record airtable.Record) (Location, error) {
 		Slug: getStringField(record.Fields, FieldSlug),
 	}, nil
 }
-
-func escapeAirtableFormulaValue(value string) string {
-	return strings.ReplaceAll(value, "'", "''")
-}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+type ctxKey struct{}
+
+// Base is the root logger; request-scoped loggers are derived from it by
+// LoggerMiddleware and carried through context.Context.
+var Base = zerolog.New(os.Stdout).With().Timestamp().Logger()
+
+// WithContext returns a copy of ctx carrying the given logger.
+func WithContext(ctx context.Context, log zerolog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, log)
+}
+
+// FromContext returns the logger attached to ctx by LoggerMiddleware, or
+// Base if none was attached (e.g. in tests or background jobs).
+func FromContext(ctx context.Context) zerolog.Logger {
+	if log, ok := ctx.Value(ctxKey{}).(zerolog.Logger); ok {
+		return log
+	}
+	return Base
+}
+
+// SetLevel updates zerolog's global level (applies to Base and every logger
+// derived from it) to one of "debug", "info", "warn", "error", etc. Unknown
+// or empty values are ignored so a bad admin-supplied level can't disable
+// logging entirely.
+func SetLevel(level string) {
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil || parsed == zerolog.NoLevel {
+		return
+	}
+	zerolog.SetGlobalLevel(parsed)
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limiter wraps a Store with a fixed limit/window, for use outside of an
+// HTTP request (e.g. throttling outbound calls to a third-party API).
+type Limiter struct {
+	store  Store
+	key    string
+	limit  int
+	window time.Duration
+}
+
+// NewLimiter creates a Limiter that allows limit events per window under a
+// single shared key (store-backed, so it's safe to share across goroutines).
+func NewLimiter(store Store, key string, limit int, window time.Duration) *Limiter {
+	return &Limiter{store: store, key: key, limit: limit, window: window}
+}
+
+// Wait blocks until the limiter has capacity for one more call, or ctx is
+// cancelled. Airtable enforces a hard 5 req/sec/base cap, so callers that
+// share a Limiter across every outbound request stay under it even when
+// several handlers hit Airtable concurrently.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		allowed, _, resetAt := l.store.Allow(l.key, l.limit, l.window)
+		if allowed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Until(resetAt)):
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// KeyFunc derives the bucket key for a request, e.g. by client IP or by the
+// authenticated user ID.
+type KeyFunc func(c *gin.Context) string
+
+// ByIP buckets requests by client IP address. Use for anonymous routes.
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUserID buckets requests by the authenticated user ID set by
+// user.AuthMiddleware. Falls back to ByIP if no user is set (the route
+// should be behind AuthMiddleware, but this keeps the middleware safe to
+// reuse on a route that isn't).
+func ByUserID(c *gin.Context) string {
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(string); ok && id != "" {
+			return "user:" + id
+		}
+	}
+	return ByIP(c)
+}
+
+// Options configures a rate-limiting middleware instance.
+type Options struct {
+	Store  Store
+	Limit  int
+	Window time.Duration
+	Key    KeyFunc
+}
+
+// Middleware enforces limit requests per window per key, responding 429
+// with response.Error and Retry-After/X-RateLimit-* headers once exceeded.
+func Middleware(opts Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := opts.Key(c)
+		allowed, remaining, resetAt := opts.Store.Allow(key, opts.Limit, opts.Window)
+
+		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", resetAt.Unix()))
+
+		if !allowed {
+			retryAfter := int(time.Until(resetAt).Seconds())
+			if retryAfter < 0 {
+				retryAfter = 0
+			}
+			c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
+			response.RateLimited(c)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Store tracks per-key request counts over fixed windows and decides
+// whether a new request should be allowed.
+type Store interface {
+	// Allow records one request against key and reports whether it fits
+	// within limit requests per window, how many requests remain in the
+	// current window, and when the window resets.
+	Allow(key string, limit int, window time.Duration) (allowed bool, remaining int, resetAt time.Time)
+}
+
+type bucket struct {
+	count   int
+	resetAt time.Time
+}
+
+// InMemoryStore is a Store backed by a map of fixed windows guarded by a
+// mutex. It is a single-process implementation; a Redis-backed Store should
+// satisfy the same interface for multi-instance deployments.
+type InMemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewInMemoryStore creates an empty in-memory rate limit store.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *InMemoryStore) Allow(key string, limit int, window time.Duration) (bool, int, time.Time) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok || now.After(b.resetAt) {
+		b = &bucket{count: 0, resetAt: now.Add(window)}
+		s.buckets[key] = b
+	}
+
+	if b.count >= limit {
+		return false, 0, b.resetAt
+	}
+
+	b.count++
+	return true, limit - b.count, b.resetAt
+}
+
+var _ Store = (*InMemoryStore)(nil)
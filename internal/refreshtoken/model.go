@@ -0,0 +1,79 @@
+// Package refreshtoken stores opaque refresh tokens used to mint new access
+// JWTs without re-entering credentials, and supports server-side revocation
+// (logout, logout-all, reuse detection) that a stateless JWT alone can't
+// provide.
+package refreshtoken
+
+import "time"
+
+// Token is one issued refresh token. Neither the raw token value nor the
+// raw user ID is stored: both are hashed so a database leak doesn't hand an
+// attacker usable session identifiers or a login->session mapping.
+type Token struct {
+	ID         string
+	UserHash   string // sha256(userID), hex-encoded
+	TokenHash  string // sha256(raw opaque token), hex-encoded
+	IssuedAt   time.Time
+	ExpiresAt  time.Time
+	RevokedAt  time.Time // zero value means not revoked
+	ReplacedBy string    // ID of the token issued when this one was rotated, if any
+	UserAgent  string
+	IP         string
+	// AccessJTI and AccessExpiresAt identify the access JWT issued alongside
+	// this refresh token, so revoking this token (logout, rotation,
+	// admin-initiated logout) can also blacklist that access token by jti
+	// via a registered revocation.Store, rather than leaving it valid until
+	// its own natural expiry. Empty/zero when issued before this existed.
+	AccessJTI       string
+	AccessExpiresAt time.Time
+}
+
+// Airtable field names, used by AirtableRepository.
+const (
+	FieldUserHash        = "UserHash"
+	FieldTokenHash       = "TokenHash"
+	FieldIssuedAt        = "IssuedAt"
+	FieldExpiresAt       = "ExpiresAt"
+	FieldRevokedAt       = "RevokedAt"
+	FieldReplacedBy      = "ReplacedBy"
+	FieldUserAgent       = "UserAgent"
+	FieldIP              = "IP"
+	FieldAccessJTI       = "AccessJTI"
+	FieldAccessExpiresAt = "AccessExpiresAt"
+)
+
+// ToAirtableFields converts t to Airtable fields format.
+func (t Token) ToAirtableFields() map[string]interface{} {
+	fields := map[string]interface{}{
+		FieldUserHash:  t.UserHash,
+		FieldTokenHash: t.TokenHash,
+		FieldIssuedAt:  t.IssuedAt.Format(time.RFC3339),
+		FieldExpiresAt: t.ExpiresAt.Format(time.RFC3339),
+		FieldUserAgent: t.UserAgent,
+		FieldIP:        t.IP,
+	}
+	if !t.RevokedAt.IsZero() {
+		fields[FieldRevokedAt] = t.RevokedAt.Format(time.RFC3339)
+	}
+	if t.ReplacedBy != "" {
+		fields[FieldReplacedBy] = t.ReplacedBy
+	}
+	if t.AccessJTI != "" {
+		fields[FieldAccessJTI] = t.AccessJTI
+		fields[FieldAccessExpiresAt] = t.AccessExpiresAt.Format(time.RFC3339)
+	}
+	return fields
+}
+
+// Active reports whether the token has not expired and has not been revoked.
+func (t Token) Active() bool {
+	return t.RevokedAt.IsZero() && time.Now().Before(t.ExpiresAt)
+}
+
+// RevokedWithoutReplacement reports whether the token was revoked but never rotated to a
+// replacement. Seeing such a token presented again means it was stolen
+// (the legitimate holder already has the replacement), so the caller should
+// revoke the rest of the family.
+func (t Token) RevokedWithoutReplacement() bool {
+	return !t.RevokedAt.IsZero() && t.ReplacedBy == ""
+}
@@ -0,0 +1,211 @@
+package refreshtoken
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"lam-phuong-api/internal/airtable"
+)
+
+// Repository defines behavior for storing and looking up refresh tokens.
+type Repository interface {
+	// Create stores a newly issued token, assigning it an ID.
+	Create(ctx context.Context, token Token) (Token, error)
+	// GetByTokenHash looks up a token by the hash of its raw value, as
+	// presented to POST /auth/refresh.
+	GetByTokenHash(ctx context.Context, tokenHash string) (Token, bool)
+	// ListByUserHash returns every token (active or not) issued to a user,
+	// newest first, for GET /auth/sessions.
+	ListByUserHash(ctx context.Context, userHash string) ([]Token, error)
+	// Revoke marks a token revoked and, when rotating rather than logging
+	// out, records the ID of its replacement.
+	Revoke(ctx context.Context, id string, replacedBy string) error
+	// RevokeAllForUser revokes every active token for a user (logout-all,
+	// and reuse-detection's "kill the whole family" response).
+	RevokeAllForUser(ctx context.Context, userHash string) error
+}
+
+// InMemoryRepository stores refresh tokens in memory and is safe for
+// concurrent access. Its tokens are lost on restart and are not shared
+// across instances.
+type InMemoryRepository struct {
+	mu     sync.RWMutex
+	data   map[string]Token
+	nextID int
+}
+
+// NewInMemoryRepository creates an empty in-memory refresh token store.
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{data: make(map[string]Token), nextID: 1}
+}
+
+// Create assigns the token an ID and stores it.
+func (r *InMemoryRepository) Create(ctx context.Context, token Token) (Token, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.ID = strconv.Itoa(r.nextID)
+	r.nextID++
+	r.data[token.ID] = token
+	return token, nil
+}
+
+// GetByTokenHash scans for a token matching tokenHash.
+func (r *InMemoryRepository) GetByTokenHash(ctx context.Context, tokenHash string) (Token, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.data {
+		if token.TokenHash == tokenHash {
+			return token, true
+		}
+	}
+	return Token{}, false
+}
+
+// ListByUserHash returns every token for userHash, most recently issued first.
+func (r *InMemoryRepository) ListByUserHash(ctx context.Context, userHash string) ([]Token, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens := make([]Token, 0)
+	for _, token := range r.data {
+		if token.UserHash == userHash {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].IssuedAt.After(tokens[j].IssuedAt)
+	})
+	return tokens, nil
+}
+
+// Revoke marks the token with the given ID revoked and records replacedBy.
+// Revoking an already-revoked token is a no-op (idempotent).
+func (r *InMemoryRepository) Revoke(ctx context.Context, id string, replacedBy string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.data[id]
+	if !ok {
+		return nil
+	}
+	if token.RevokedAt.IsZero() {
+		token.RevokedAt = time.Now()
+		token.ReplacedBy = replacedBy
+	}
+	r.data[id] = token
+	return nil
+}
+
+// RevokeAllForUser revokes every currently-active token belonging to userHash.
+func (r *InMemoryRepository) RevokeAllForUser(ctx context.Context, userHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.data {
+		if token.UserHash == userHash && token.RevokedAt.IsZero() {
+			token.RevokedAt = time.Now()
+			r.data[id] = token
+		}
+	}
+	return nil
+}
+
+var _ Repository = (*InMemoryRepository)(nil)
+
+// AirtableRepository wraps an InMemoryRepository and adds Airtable
+// persistence, mirroring user.AirtableRepository: reads that exist to
+// support the hot hash-lookup auth path (GetByTokenHash, ListByUserHash)
+// are served from the in-memory copy, while every mutation also syncs to
+// Airtable on a best-effort basis. Nothing loads those records back: a new
+// process starts with an empty in-memory copy, so tokens issued before a
+// restart are not found by it.
+type AirtableRepository struct {
+	repo           *InMemoryRepository
+	airtableClient *airtable.Client
+	airtableTable  string
+}
+
+// NewAirtableRepository creates a repository that syncs to Airtable.
+func NewAirtableRepository(airtableClient *airtable.Client, airtableTable string) *AirtableRepository {
+	return &AirtableRepository{
+		repo:           NewInMemoryRepository(),
+		airtableClient: airtableClient,
+		airtableTable:  airtableTable,
+	}
+}
+
+// Create stores token in memory, assigning it an ID, and syncs it to
+// Airtable. A sync failure is logged but doesn't fail the call: the token
+// is already usable via the in-memory copy.
+func (r *AirtableRepository) Create(ctx context.Context, token Token) (Token, error) {
+	created, err := r.repo.Create(ctx, token)
+	if err != nil {
+		return Token{}, err
+	}
+
+	if _, err := r.airtableClient.CreateRecord(ctx, r.airtableTable, created.ToAirtableFields()); err != nil {
+		log.Printf("Failed to save refresh token to Airtable: %v", err)
+	}
+	return created, nil
+}
+
+// GetByTokenHash looks up tokenHash in the in-memory copy.
+func (r *AirtableRepository) GetByTokenHash(ctx context.Context, tokenHash string) (Token, bool) {
+	return r.repo.GetByTokenHash(ctx, tokenHash)
+}
+
+// ListByUserHash looks up userHash in the in-memory copy.
+func (r *AirtableRepository) ListByUserHash(ctx context.Context, userHash string) ([]Token, error) {
+	return r.repo.ListByUserHash(ctx, userHash)
+}
+
+// Revoke updates the in-memory copy and syncs the revocation to Airtable.
+func (r *AirtableRepository) Revoke(ctx context.Context, id string, replacedBy string) error {
+	if err := r.repo.Revoke(ctx, id, replacedBy); err != nil {
+		return err
+	}
+
+	token, ok := r.repo.data[id]
+	if !ok {
+		return nil
+	}
+	if _, err := r.airtableClient.UpdateRecordPartial(ctx, r.airtableTable, id, token.ToAirtableFields()); err != nil {
+		log.Printf("Failed to sync refresh token revocation to Airtable: %v", err)
+	}
+	return nil
+}
+
+// RevokeAllForUser updates the in-memory copy and syncs each revoked token
+// to Airtable.
+func (r *AirtableRepository) RevokeAllForUser(ctx context.Context, userHash string) error {
+	tokens, err := r.repo.ListByUserHash(ctx, userHash)
+	if err != nil {
+		return err
+	}
+
+	if err := r.repo.RevokeAllForUser(ctx, userHash); err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if !token.RevokedAt.IsZero() {
+			continue // already revoked before this call; nothing changed
+		}
+		revoked, ok := r.repo.data[token.ID]
+		if !ok {
+			continue
+		}
+		if _, err := r.airtableClient.UpdateRecordPartial(ctx, r.airtableTable, token.ID, revoked.ToAirtableFields()); err != nil {
+			log.Printf("Failed to sync refresh token revocation to Airtable: %v", err)
+		}
+	}
+	return nil
+}
+
+var _ Repository = (*AirtableRepository)(nil)
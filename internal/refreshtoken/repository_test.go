@@ -0,0 +1,156 @@
+package refreshtoken
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemoryRepositoryCreateAssignsIncrementingIDs(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+
+	first, err := repo.Create(ctx, Token{TokenHash: Hash("a")})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	second, err := repo.Create(ctx, Token{TokenHash: Hash("b")})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+	if first.ID == "" || first.ID == second.ID {
+		t.Errorf("expected distinct, non-empty IDs, got %q and %q", first.ID, second.ID)
+	}
+}
+
+func TestInMemoryRepositoryGetByTokenHash(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, Token{TokenHash: Hash("raw-value")})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	found, ok := repo.GetByTokenHash(ctx, Hash("raw-value"))
+	if !ok || found.ID != created.ID {
+		t.Errorf("GetByTokenHash did not find the token just created")
+	}
+
+	if _, ok := repo.GetByTokenHash(ctx, Hash("never-issued")); ok {
+		t.Error("GetByTokenHash found a token that was never created")
+	}
+}
+
+func TestInMemoryRepositoryRevokeIsIdempotent(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, Token{TokenHash: Hash("raw")})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	if err := repo.Revoke(ctx, created.ID, "replacement-id"); err != nil {
+		t.Fatalf("Revoke returned an error: %v", err)
+	}
+	revoked, _ := repo.GetByTokenHash(ctx, Hash("raw"))
+	firstRevokedAt := revoked.RevokedAt
+	if firstRevokedAt.IsZero() {
+		t.Fatal("expected RevokedAt to be set after Revoke")
+	}
+	if revoked.ReplacedBy != "replacement-id" {
+		t.Errorf("ReplacedBy = %q, want %q", revoked.ReplacedBy, "replacement-id")
+	}
+
+	if err := repo.Revoke(ctx, created.ID, "replacement-id"); err != nil {
+		t.Fatalf("second Revoke call returned an error: %v", err)
+	}
+	revokedAgain, _ := repo.GetByTokenHash(ctx, Hash("raw"))
+	if !revokedAgain.RevokedAt.Equal(firstRevokedAt) {
+		t.Error("re-revoking an already-revoked token should not move RevokedAt")
+	}
+
+	if err := repo.Revoke(ctx, "nonexistent", "x"); err != nil {
+		t.Errorf("Revoke on an unknown ID should be a no-op, got error: %v", err)
+	}
+}
+
+func TestInMemoryRepositoryRevokeAllForUser(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+	userHash := Hash("user-1")
+	otherHash := Hash("user-2")
+
+	repo.Create(ctx, Token{UserHash: userHash, TokenHash: Hash("a")})
+	repo.Create(ctx, Token{UserHash: userHash, TokenHash: Hash("b")})
+	other, _ := repo.Create(ctx, Token{UserHash: otherHash, TokenHash: Hash("c")})
+
+	if err := repo.RevokeAllForUser(ctx, userHash); err != nil {
+		t.Fatalf("RevokeAllForUser returned an error: %v", err)
+	}
+
+	tokens, err := repo.ListByUserHash(ctx, userHash)
+	if err != nil {
+		t.Fatalf("ListByUserHash returned an error: %v", err)
+	}
+	for _, tok := range tokens {
+		if tok.RevokedAt.IsZero() {
+			t.Errorf("token %s belonging to the revoked user should be revoked", tok.ID)
+		}
+	}
+
+	untouched, ok := repo.GetByTokenHash(ctx, Hash("c"))
+	if !ok || untouched.ID != other.ID {
+		t.Fatal("could not find the other user's token")
+	}
+	if !untouched.RevokedAt.IsZero() {
+		t.Error("RevokeAllForUser should not revoke another user's tokens")
+	}
+}
+
+func TestListByUserHashNewestFirst(t *testing.T) {
+	repo := NewInMemoryRepository()
+	ctx := context.Background()
+	userHash := Hash("user-1")
+
+	now := time.Now()
+	repo.Create(ctx, Token{UserHash: userHash, TokenHash: Hash("old"), IssuedAt: now.Add(-time.Hour)})
+	repo.Create(ctx, Token{UserHash: userHash, TokenHash: Hash("new"), IssuedAt: now})
+
+	tokens, err := repo.ListByUserHash(ctx, userHash)
+	if err != nil {
+		t.Fatalf("ListByUserHash returned an error: %v", err)
+	}
+	if len(tokens) != 2 {
+		t.Fatalf("expected 2 tokens, got %d", len(tokens))
+	}
+	if tokens[0].TokenHash != Hash("new") {
+		t.Error("ListByUserHash should return the most recently issued token first")
+	}
+}
+
+func TestTokenActiveAndRevokedWithoutReplacement(t *testing.T) {
+	active := Token{ExpiresAt: time.Now().Add(time.Hour)}
+	if !active.Active() {
+		t.Error("a non-expired, non-revoked token should be Active")
+	}
+
+	expired := Token{ExpiresAt: time.Now().Add(-time.Hour)}
+	if expired.Active() {
+		t.Error("an expired token should not be Active")
+	}
+
+	revokedWithoutReplacement := Token{ExpiresAt: time.Now().Add(time.Hour), RevokedAt: time.Now()}
+	if revokedWithoutReplacement.Active() {
+		t.Error("a revoked token should not be Active")
+	}
+	if !revokedWithoutReplacement.RevokedWithoutReplacement() {
+		t.Error("a revoked token with no ReplacedBy should be flagged as stolen-token evidence")
+	}
+
+	rotated := Token{RevokedAt: time.Now(), ReplacedBy: "new-id"}
+	if rotated.RevokedWithoutReplacement() {
+		t.Error("a token revoked via rotation (ReplacedBy set) should not be flagged as reused")
+	}
+}
@@ -0,0 +1,26 @@
+package refreshtoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateRawToken produces a new opaque refresh token. Only Hash(raw) is
+// ever persisted; the raw value is returned once, to the client, and never
+// stored.
+func GenerateRawToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Hash returns the hex-encoded sha256 digest of value, used for both
+// UserHash (from a user ID) and TokenHash (from a raw token).
+func Hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,268 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Retry tuning for transient Apply failures, mirrored from internal/job's
+// worker so both subsystems back off the same way under Airtable's rate
+// limits.
+const (
+	maxAttempts    = 3
+	retryBaseDelay = 200 * time.Millisecond
+)
+
+// ApplyFunc mirrors one Event (or, for scheduled/manual runs, a zero-value
+// Event carrying just Table) into a Target. Packages that own a replicated
+// table register the ApplyFunc that knows how to talk to that table's
+// destinations.
+type ApplyFunc func(ctx context.Context, target Target, event Event) error
+
+type run struct {
+	policy Policy
+	target Target
+	event  Event
+}
+
+// Engine fans Events out to every enabled Policy registered for the event's
+// table, and drives each Policy's cron schedule for full, non-event-driven
+// runs. It owns a fixed worker pool draining an internal queue, the same
+// shape as internal/job.Service.
+type Engine struct {
+	mu       sync.RWMutex
+	policies PolicyStore
+	targets  TargetStore
+	applies  map[string]ApplyFunc
+	queue    chan run
+	cron     *cron.Cron
+	entryIDs map[string]cron.EntryID
+}
+
+// NewEngine creates an Engine backed by the given stores, with the given
+// number of worker goroutines draining its queue.
+func NewEngine(policies PolicyStore, targets TargetStore, workers int) *Engine {
+	if workers <= 0 {
+		workers = 1
+	}
+	e := &Engine{
+		policies: policies,
+		targets:  targets,
+		applies:  make(map[string]ApplyFunc),
+		queue:    make(chan run, 256),
+		cron:     cron.New(),
+		entryIDs: make(map[string]cron.EntryID),
+	}
+	for i := 0; i < workers; i++ {
+		go e.worker()
+	}
+	return e
+}
+
+// RegisterApply associates an ApplyFunc with the table it knows how to
+// mirror. Call this from the package that owns the table, typically from
+// an init().
+func (e *Engine) RegisterApply(table string, apply ApplyFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.applies[table] = apply
+}
+
+// Start begins the cron scheduler, running enabled policies' CronExpr on
+// schedule.
+func (e *Engine) Start() { e.cron.Start() }
+
+// Stop halts the cron scheduler. It does not wait for runs that are already
+// queued or in progress.
+func (e *Engine) Stop() { e.cron.Stop() }
+
+// Emit queues event for every enabled Policy registered against event.Table.
+// Repositories call this after a successful Create/DeleteBySlug; it never
+// blocks the caller on the replication run itself.
+func (e *Engine) Emit(ctx context.Context, event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	for _, policy := range e.policies.List() {
+		if !policy.Enabled || policy.Table != event.Table {
+			continue
+		}
+		target, ok := e.targets.Get(policy.TargetID)
+		if !ok {
+			log.Printf("replication: policy %s references unknown target %s", policy.ID, policy.TargetID)
+			continue
+		}
+		policy.TriggeredBy = TriggeredEvent
+		e.queue <- run{policy: policy, target: target, event: event}
+	}
+	return nil
+}
+
+// CreatePolicy registers a new policy and schedules it with cron if enabled
+// and a CronExpr is set.
+func (e *Engine) CreatePolicy(p Policy) (Policy, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.targets.Get(p.TargetID); !ok {
+		return Policy{}, fmt.Errorf("unknown target %q", p.TargetID)
+	}
+	p.Status = StatusIdle
+	p.UpdateTime = time.Now()
+	if err := e.policies.Save(p); err != nil {
+		return Policy{}, err
+	}
+	if p.Enabled && p.CronExpr != "" {
+		if err := e.scheduleLocked(p); err != nil {
+			return Policy{}, err
+		}
+	}
+	return p, nil
+}
+
+// UpdatePolicy replaces a policy's schedule and enabled flag, rescheduling
+// it with the cron runner.
+func (e *Engine) UpdatePolicy(id, cronExpr string, enabled bool) (Policy, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	policy, ok := e.policies.Get(id)
+	if !ok {
+		return Policy{}, fmt.Errorf("unknown policy %q", id)
+	}
+
+	if entryID, scheduled := e.entryIDs[id]; scheduled {
+		e.cron.Remove(entryID)
+		delete(e.entryIDs, id)
+	}
+
+	policy.CronExpr = cronExpr
+	policy.Enabled = enabled
+	if err := e.policies.Save(policy); err != nil {
+		return Policy{}, err
+	}
+
+	if enabled && cronExpr != "" {
+		if err := e.scheduleLocked(policy); err != nil {
+			return Policy{}, err
+		}
+	}
+	return policy, nil
+}
+
+// DeletePolicy removes a policy and its cron schedule, if any.
+func (e *Engine) DeletePolicy(id string) bool {
+	e.mu.Lock()
+	if entryID, scheduled := e.entryIDs[id]; scheduled {
+		e.cron.Remove(entryID)
+		delete(e.entryIDs, id)
+	}
+	e.mu.Unlock()
+	return e.policies.Delete(id)
+}
+
+func (e *Engine) scheduleLocked(policy Policy) error {
+	entryID, err := e.cron.AddFunc(policy.CronExpr, func() {
+		if err := e.TriggerNow(policy.ID, TriggeredScheduled); err != nil {
+			log.Printf("replication: scheduled run of policy %s failed: %v", policy.ID, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %q for policy %s: %w", policy.CronExpr, policy.ID, err)
+	}
+	e.entryIDs[policy.ID] = entryID
+	return nil
+}
+
+// TriggerNow queues a full, non-event-driven run of policy outside its cron
+// schedule, recording triggeredBy ("manual" or "scheduled") on the policy.
+func (e *Engine) TriggerNow(policyID string, triggeredBy TriggerSource) error {
+	policy, ok := e.policies.Get(policyID)
+	if !ok {
+		return fmt.Errorf("unknown policy %q", policyID)
+	}
+	target, ok := e.targets.Get(policy.TargetID)
+	if !ok {
+		return fmt.Errorf("policy %s references unknown target %q", policyID, policy.TargetID)
+	}
+
+	policy.TriggeredBy = triggeredBy
+	e.queue <- run{policy: policy, target: target, event: Event{Table: policy.Table, Time: time.Now()}}
+	return nil
+}
+
+// Policies returns a snapshot of all registered policies.
+func (e *Engine) Policies() []Policy { return e.policies.List() }
+
+// Targets returns a snapshot of all registered targets.
+func (e *Engine) Targets() []Target { return e.targets.List() }
+
+// CreateTarget registers a new replication target.
+func (e *Engine) CreateTarget(t Target) (Target, error) {
+	if err := e.targets.Save(t); err != nil {
+		return Target{}, err
+	}
+	return t, nil
+}
+
+func (e *Engine) worker() {
+	for r := range e.queue {
+		e.run(r)
+	}
+}
+
+func (e *Engine) run(r run) {
+	e.mu.RLock()
+	apply, ok := e.applies[r.policy.Table]
+	e.mu.RUnlock()
+	if !ok {
+		log.Printf("replication: no Apply registered for table %s, dropping run for policy %s", r.policy.Table, r.policy.ID)
+		return
+	}
+
+	r.policy.Status = StatusRunning
+	r.policy.StartTime = time.Now()
+	e.save(r.policy)
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = apply(context.Background(), r.target, r.event)
+		if err == nil {
+			break
+		}
+		if attempt < maxAttempts {
+			time.Sleep(retryBaseDelay << (attempt - 1))
+		}
+	}
+
+	r.policy.UpdateTime = time.Now()
+	if err != nil {
+		r.policy.Status = StatusFailed
+		r.policy.LastError = fmt.Sprintf("failed after %d attempts: %s", maxAttempts, err.Error())
+	} else {
+		r.policy.Status = StatusSucceed
+		r.policy.LastError = ""
+	}
+	e.save(r.policy)
+}
+
+func (e *Engine) save(p Policy) {
+	if err := e.policies.Save(p); err != nil {
+		log.Printf("replication: failed to save policy %s: %v", p.ID, err)
+	}
+}
+
+// Emitter is the narrow interface repositories depend on to report
+// mutations; satisfied by *Engine. Declaring it separately keeps repository
+// packages from importing the engine's scheduling/worker internals.
+type Emitter interface {
+	Emit(ctx context.Context, event Event) error
+}
+
+var _ Emitter = (*Engine)(nil)
@@ -0,0 +1,231 @@
+package replication
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// Handler exposes admin endpoints for managing replication targets and
+// policies. Callers should guard RegisterRoutes' group with
+// user.RequireRole(user.RoleSuperAdmin).
+type Handler struct {
+	engine *Engine
+}
+
+// NewHandler creates a handler backed by the given Engine.
+func NewHandler(engine *Engine) *Handler {
+	return &Handler{engine: engine}
+}
+
+// RegisterRoutes attaches replication routes to the supplied router group.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/replication/targets", h.ListTargets)
+	router.POST("/replication/targets", h.CreateTarget)
+	router.GET("/replication/policies", h.ListPolicies)
+	router.POST("/replication/policies", h.CreatePolicy)
+	router.PUT("/replication/policies/:id", h.UpdatePolicy)
+	router.DELETE("/replication/policies/:id", h.DeletePolicy)
+	router.POST("/replication/policies/:id/trigger", h.TriggerPolicy)
+}
+
+// ListTargets godoc
+// @Summary      List replication targets
+// @Description  Get every registered replication destination (requires super admin role)
+// @Tags         replication
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "Targets retrieved successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Router       /replication/targets [get]
+func (h *Handler) ListTargets(c *gin.Context) {
+	response.Success(c, http.StatusOK, h.engine.Targets(), "Targets retrieved successfully")
+}
+
+type targetPayload struct {
+	ID     string            `json:"id" binding:"required"`
+	Name   string            `json:"name" binding:"required"`
+	Type   DestinationType   `json:"type" binding:"required"`
+	Config map[string]string `json:"config"`
+}
+
+// CreateTarget godoc
+// @Summary      Register a replication target
+// @Description  Add a secondary datastore (Postgres, another Airtable base, or S3) policies can mirror into (requires super admin role)
+// @Tags         replication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        target  body      targetPayload  true  "Target payload"
+// @Success      201     {object}  response.Response  "Target created successfully"
+// @Failure      400     {object}  response.ErrorResponse  "Validation error"
+// @Failure      401     {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  response.ErrorResponse  "Forbidden"
+// @Router       /replication/targets [post]
+func (h *Handler) CreateTarget(c *gin.Context) {
+	var payload targetPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	target, err := h.engine.CreateTarget(Target{
+		ID:     payload.ID,
+		Name:   payload.Name,
+		Type:   payload.Type,
+		Config: payload.Config,
+	})
+	if err != nil {
+		response.InternalError(c, "Failed to create target: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, target, "Target created successfully")
+}
+
+// ListPolicies godoc
+// @Summary      List replication policies
+// @Description  Get every table's replication policy and its most recent run status (requires super admin role)
+// @Tags         replication
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "Policies retrieved successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Router       /replication/policies [get]
+func (h *Handler) ListPolicies(c *gin.Context) {
+	response.Success(c, http.StatusOK, h.engine.Policies(), "Policies retrieved successfully")
+}
+
+type policyPayload struct {
+	ID       string `json:"id" binding:"required"`
+	Table    string `json:"table" binding:"required"`
+	TargetID string `json:"target_id" binding:"required"`
+	CronExpr string `json:"cron_str"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// CreatePolicy godoc
+// @Summary      Create a replication policy
+// @Description  Mirror a table into a target on a cron schedule and/or as writes happen (requires super admin role)
+// @Tags         replication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        policy  body      policyPayload  true  "Policy payload"
+// @Success      201     {object}  response.Response  "Policy created successfully"
+// @Failure      400     {object}  response.ErrorResponse  "Validation error"
+// @Failure      401     {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  response.ErrorResponse  "Forbidden"
+// @Router       /replication/policies [post]
+func (h *Handler) CreatePolicy(c *gin.Context) {
+	var payload policyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.engine.CreatePolicy(Policy{
+		ID:       payload.ID,
+		Table:    payload.Table,
+		TargetID: payload.TargetID,
+		CronExpr: payload.CronExpr,
+		Enabled:  payload.Enabled,
+	})
+	if err != nil {
+		response.ValidationError(c, "Failed to create policy", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	response.Success(c, http.StatusCreated, policy, "Policy created successfully")
+}
+
+type updatePolicyPayload struct {
+	CronExpr string `json:"cron_str"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// UpdatePolicy godoc
+// @Summary      Update a replication policy
+// @Description  Change a policy's cron schedule and enabled flag (requires super admin role)
+// @Tags         replication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string               true  "Policy ID"
+// @Param        policy  body      updatePolicyPayload  true  "Policy update"
+// @Success      200     {object}  response.Response  "Policy updated successfully"
+// @Failure      400     {object}  response.ErrorResponse  "Validation error"
+// @Failure      401     {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  response.ErrorResponse  "Forbidden"
+// @Failure      404     {object}  response.ErrorResponse  "Unknown policy"
+// @Router       /replication/policies/{id} [put]
+func (h *Handler) UpdatePolicy(c *gin.Context) {
+	id := c.Param("id")
+
+	var payload updatePolicyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.engine.UpdatePolicy(id, payload.CronExpr, payload.Enabled)
+	if err != nil {
+		response.NotFound(c, "Replication policy")
+		return
+	}
+
+	response.Success(c, http.StatusOK, policy, "Policy updated successfully")
+}
+
+// DeletePolicy godoc
+// @Summary      Delete a replication policy
+// @Description  Remove a policy and its cron schedule (requires super admin role)
+// @Tags         replication
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Policy ID"
+// @Success      200  {object}  response.Response  "Policy deleted successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Failure      404  {object}  response.ErrorResponse  "Unknown policy"
+// @Router       /replication/policies/{id} [delete]
+func (h *Handler) DeletePolicy(c *gin.Context) {
+	id := c.Param("id")
+	if ok := h.engine.DeletePolicy(id); !ok {
+		response.NotFound(c, "Replication policy")
+		return
+	}
+	response.SuccessNoContent(c, "Policy deleted successfully")
+}
+
+// TriggerPolicy godoc
+// @Summary      Force an immediate replication run
+// @Description  Run a policy immediately, outside its cron schedule (requires super admin role)
+// @Tags         replication
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "Policy ID"
+// @Success      202  {object}  response.Response  "Replication run triggered"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Failure      404  {object}  response.ErrorResponse  "Unknown policy"
+// @Router       /replication/policies/{id}/trigger [post]
+func (h *Handler) TriggerPolicy(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.engine.TriggerNow(id, TriggeredManual); err != nil {
+		response.NotFound(c, "Replication policy")
+		return
+	}
+	response.Success(c, http.StatusAccepted, gin.H{"policy_id": id}, "Replication run triggered")
+}
@@ -0,0 +1,76 @@
+package replication
+
+import "time"
+
+// DestinationType identifies the kind of backend a Target writes to.
+type DestinationType string
+
+// Supported destination kinds. Only the Apply function registered for a
+// policy's table actually knows how to talk to the backend; the engine
+// itself is destination-agnostic.
+const (
+	DestinationPostgres DestinationType = "postgres"
+	DestinationAirtable DestinationType = "airtable"
+	DestinationS3       DestinationType = "s3"
+)
+
+// Target describes one secondary datastore a table can be mirrored into.
+type Target struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Type   DestinationType   `json:"type"`
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// TriggerSource records what caused a policy run.
+type TriggerSource string
+
+const (
+	TriggeredManual    TriggerSource = "manual"
+	TriggeredScheduled TriggerSource = "scheduled"
+	TriggeredEvent     TriggerSource = "event"
+)
+
+// Status is the lifecycle state of a policy's most recent run.
+type Status string
+
+const (
+	StatusIdle    Status = "idle"
+	StatusRunning Status = "running"
+	StatusSucceed Status = "succeeded"
+	StatusFailed  Status = "failed"
+)
+
+// Policy describes how one Airtable table is mirrored into a Target: the
+// destination, a cron schedule for periodic full runs, and the state of the
+// most recent run. Loosely modeled on Harbor's replication_policy.
+type Policy struct {
+	ID          string        `json:"id"`
+	Table       string        `json:"table"`
+	TargetID    string        `json:"target_id"`
+	CronExpr    string        `json:"cron_str,omitempty"`
+	Enabled     bool          `json:"enabled"`
+	TriggeredBy TriggerSource `json:"triggered_by,omitempty"`
+	Status      Status        `json:"status"`
+	StartTime   time.Time     `json:"start_time,omitempty"`
+	UpdateTime  time.Time     `json:"update_time,omitempty"`
+	LastError   string        `json:"last_error,omitempty"`
+}
+
+// Action identifies the repository mutation that produced an Event.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionDelete Action = "delete"
+)
+
+// Event is a single repository mutation queued for replication. Engine.Emit
+// fans it out to every enabled Policy registered for Table.
+type Event struct {
+	Table      string                 `json:"table"`
+	Action     Action                 `json:"action"`
+	ResourceID string                 `json:"resource_id"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Time       time.Time              `json:"time"`
+}
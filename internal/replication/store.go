@@ -0,0 +1,117 @@
+package replication
+
+import "sync"
+
+// PolicyStore persists replication policies, keyed by ID.
+type PolicyStore interface {
+	List() []Policy
+	Get(id string) (Policy, bool)
+	Save(p Policy) error
+	Delete(id string) bool
+}
+
+// TargetStore persists replication targets, keyed by ID.
+type TargetStore interface {
+	List() []Target
+	Get(id string) (Target, bool)
+	Save(t Target) error
+	Delete(id string) bool
+}
+
+// InMemoryPolicyStore is a PolicyStore backed by a map, safe for concurrent
+// use. A future AirtableStore can satisfy the same interface to persist
+// policies across restarts.
+type InMemoryPolicyStore struct {
+	mu   sync.RWMutex
+	data map[string]Policy
+}
+
+// NewInMemoryPolicyStore creates an empty in-memory policy store.
+func NewInMemoryPolicyStore() *InMemoryPolicyStore {
+	return &InMemoryPolicyStore{data: make(map[string]Policy)}
+}
+
+func (s *InMemoryPolicyStore) List() []Policy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Policy, 0, len(s.data))
+	for _, p := range s.data {
+		out = append(out, p)
+	}
+	return out
+}
+
+func (s *InMemoryPolicyStore) Get(id string) (Policy, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.data[id]
+	return p, ok
+}
+
+func (s *InMemoryPolicyStore) Save(p Policy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[p.ID] = p
+	return nil
+}
+
+func (s *InMemoryPolicyStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[id]; !ok {
+		return false
+	}
+	delete(s.data, id)
+	return true
+}
+
+var _ PolicyStore = (*InMemoryPolicyStore)(nil)
+
+// InMemoryTargetStore is a TargetStore backed by a map, safe for concurrent use.
+type InMemoryTargetStore struct {
+	mu   sync.RWMutex
+	data map[string]Target
+}
+
+// NewInMemoryTargetStore creates an empty in-memory target store.
+func NewInMemoryTargetStore() *InMemoryTargetStore {
+	return &InMemoryTargetStore{data: make(map[string]Target)}
+}
+
+func (s *InMemoryTargetStore) List() []Target {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Target, 0, len(s.data))
+	for _, t := range s.data {
+		out = append(out, t)
+	}
+	return out
+}
+
+func (s *InMemoryTargetStore) Get(id string) (Target, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.data[id]
+	return t, ok
+}
+
+func (s *InMemoryTargetStore) Save(t Target) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[t.ID] = t
+	return nil
+}
+
+func (s *InMemoryTargetStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[id]; !ok {
+		return false
+	}
+	delete(s.data, id)
+	return true
+}
+
+var _ TargetStore = (*InMemoryTargetStore)(nil)
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/logger"
 )
 
 // Response represents a standard API response structure
@@ -45,10 +46,19 @@ const (
 	ErrCodeExpiredToken   = "EXPIRED_TOKEN"
 	ErrCodeInvalidAuth    = "INVALID_AUTH"
 	ErrCodeDuplicateEmail = "DUPLICATE_EMAIL"
+	ErrCodeRateLimited    = "RATE_LIMITED"
 )
 
-// Success sends a successful response with data
+// Success sends a successful response with data, logging it through the
+// request's correlation-ID-scoped logger so the line joins the rest of the
+// request's log output.
 func Success(c *gin.Context, statusCode int, data interface{}, message string) {
+	log := logger.FromContext(c.Request.Context())
+	log.Debug().
+		Int("status", statusCode).
+		Str("message", message).
+		Msg("response sent")
+
 	c.JSON(statusCode, Response{
 		Success: true,
 		Data:    data,
@@ -64,8 +74,41 @@ func SuccessNoContent(c *gin.Context, message string) {
 	})
 }
 
-// Error sends an error response
+// Error sends an error response, logging it through the request's
+// correlation-ID-scoped logger so the line joins the rest of the request's
+// log output.
 func Error(c *gin.Context, statusCode int, code string, message string, details map[string]interface{}) {
+	log := logger.FromContext(c.Request.Context())
+	log.Warn().
+		Int("status", statusCode).
+		Str("code", code).
+		Str("message", message).
+		Msg("error response sent")
+
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+	})
+}
+
+// WriteError is Error plus the real, unsanitized cause: err is logged in
+// full server-side, while message (not err.Error()) is what the client
+// sees. Handlers that have an underlying error to report should prefer
+// this over building the client message by concatenating err.Error()
+// into it, which leaks internal detail to the caller.
+func WriteError(c *gin.Context, statusCode int, code string, message string, err error, details map[string]interface{}) {
+	log := logger.FromContext(c.Request.Context())
+	log.Warn().
+		Err(err).
+		Int("status", statusCode).
+		Str("code", code).
+		Str("message", message).
+		Msg("error response sent")
+
 	c.JSON(statusCode, Response{
 		Success: false,
 		Error: &ErrorInfo{
@@ -133,3 +176,8 @@ func InvalidAuth(c *gin.Context, message string) {
 func DuplicateEmail(c *gin.Context) {
 	Error(c, http.StatusConflict, ErrCodeDuplicateEmail, "Email already registered", nil)
 }
+
+// RateLimited sends a 429 Too Many Requests error response
+func RateLimited(c *gin.Context) {
+	Error(c, http.StatusTooManyRequests, ErrCodeRateLimited, "Rate limit exceeded", nil)
+}
@@ -0,0 +1,61 @@
+// Package revocation blacklists access-token jtis so a revoked token stops
+// working immediately instead of waiting out the rest of its lifetime, the
+// access-token-side complement to internal/refreshtoken's session store.
+package revocation
+
+import (
+	"sync"
+	"time"
+)
+
+// Store records revoked jtis until their token's own expiry, after which
+// they're pruned (there's no point remembering a jti the signature check
+// would already reject on expiry grounds alone). Implementations must be
+// safe for concurrent use.
+type Store interface {
+	// Revoke blacklists jti until expiresAt.
+	Revoke(jti string, expiresAt time.Time) error
+	// IsRevoked reports whether jti is currently blacklisted.
+	IsRevoked(jti string) bool
+}
+
+// InMemoryStore is a Store backed by a map, safe for concurrent use.
+// Revocations do not survive a process restart; like refreshtoken's
+// InMemoryRepository, that's acceptable here because a restarted process
+// also invalidates every refresh-token session tracked the same way.
+type InMemoryStore struct {
+	mu    sync.Mutex
+	items map[string]time.Time
+}
+
+// NewInMemoryStore creates an empty in-memory revocation store.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{items: make(map[string]time.Time)}
+}
+
+// Revoke blacklists jti until expiresAt.
+func (s *InMemoryStore) Revoke(jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[jti] = expiresAt
+	return nil
+}
+
+// IsRevoked reports whether jti is blacklisted, pruning it first if its
+// expiry has already passed.
+func (s *InMemoryStore) IsRevoked(jti string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.items[jti]
+	if !ok {
+		return false
+	}
+	if time.Now().After(expiresAt) {
+		delete(s.items, jti)
+		return false
+	}
+	return true
+}
+
+var _ Store = (*InMemoryStore)(nil)
@@ -0,0 +1,37 @@
+package role
+
+import (
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// RequirePermission creates a middleware that allows the request only if the
+// authenticated user's role (via Role.Has) is granted every bit in p. It
+// reads the role from the "user_role" context key stamped by
+// user.AuthMiddleware, the same key user.RequireRole and authz.RequirePermission
+// use, so route groups can mix all three gating styles.
+func RequirePermission(p Permissions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, exists := c.Get("user_role")
+		if !exists {
+			response.Forbidden(c, "User role not found in context")
+			c.Abort()
+			return
+		}
+
+		roleStr, ok := userRole.(string)
+		if !ok {
+			response.Forbidden(c, "Invalid user role type")
+			c.Abort()
+			return
+		}
+
+		if !Role(roleStr).Has(p) {
+			response.Forbidden(c, "Insufficient permissions for this action")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+// Package role defines the Permissions bitmask granted to each of this
+// service's three role tiers and the hierarchy rule used to decide whether
+// one role outranks another (e.g. before letting it change a user's role).
+package role
+
+// Role identifies a principal's tier. Values line up with the flat role
+// strings user.RoleSuperAdmin/RoleAdmin/RoleUser already in use. This
+// package can't import user: user.Claims needs to reference Permissions
+// from HasPermission, so the dependency has to run the other way. authz.Role
+// is a separate type serving authz's own, differently-shaped policy table.
+type Role string
+
+// Known roles. Keep these in sync with user.RoleSuperAdmin/RoleAdmin/RoleUser.
+const (
+	SuperAdmin Role = "Super Admin"
+	Admin      Role = "Admin"
+	User       Role = "User"
+)
+
+// Permissions is a bitmask of fine-grained capabilities a Role may hold.
+type Permissions uint32
+
+// Known permissions.
+const (
+	PermManageUsers Permissions = 1 << iota
+	PermManageJobs
+	PermManageCategories
+	PermViewAudit
+	// PermManageSystem is held only by SuperAdmin: cache/replication policy
+	// admin and updating arbitrary users (see superAdminRoutes in
+	// server.NewRouter), the one tier of action Admin never gets regardless
+	// of rank.
+	PermManageSystem
+)
+
+// permissionsByRole maps each Role to the full set of Permissions it holds,
+// built so each tier's set is a strict superset of the tier below it
+// (SuperAdmin ⊃ Admin ⊃ User), enforced by construction rather than a
+// runtime subset check.
+var permissionsByRole = map[Role]Permissions{
+	User:       PermManageJobs | PermManageCategories,
+	Admin:      PermManageJobs | PermManageCategories | PermManageUsers | PermViewAudit,
+	SuperAdmin: PermManageJobs | PermManageCategories | PermManageUsers | PermViewAudit | PermManageSystem,
+}
+
+// rank orders roles from lowest to highest privilege, used by Outranks.
+// Starts at 1 (not 0) so an unrecognized Role - which reads as the map's
+// zero value, rank 0 - ranks below every known Role, including User.
+var rank = map[Role]int{
+	User:       1,
+	Admin:      2,
+	SuperAdmin: 3,
+}
+
+// Permissions returns the full permission set granted to r. An unrecognized
+// Role grants nothing.
+func (r Role) Permissions() Permissions {
+	return permissionsByRole[r]
+}
+
+// Has reports whether r's permission set includes every bit set in p.
+func (r Role) Has(p Permissions) bool {
+	return r.Permissions()&p == p
+}
+
+// Outranks reports whether a has strictly greater privilege than b. An
+// unrecognized Role ranks below every known Role.
+func Outranks(a, b Role) bool {
+	return rank[a] > rank[b]
+}
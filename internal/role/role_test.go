@@ -0,0 +1,44 @@
+package role
+
+import "testing"
+
+func TestRoleHasIsASupersetByTier(t *testing.T) {
+	if !SuperAdmin.Has(Admin.Permissions()) {
+		t.Error("SuperAdmin should have every permission Admin has")
+	}
+	if !Admin.Has(User.Permissions()) {
+		t.Error("Admin should have every permission User has")
+	}
+	if User.Has(PermManageUsers) {
+		t.Error("User should not have PermManageUsers")
+	}
+	if !Admin.Has(PermManageUsers | PermViewAudit) {
+		t.Error("Admin should have both PermManageUsers and PermViewAudit")
+	}
+}
+
+func TestRoleHasUnrecognizedRoleGrantsNothing(t *testing.T) {
+	if Role("bogus").Has(PermManageJobs) {
+		t.Error("an unrecognized Role should not hold any permission")
+	}
+}
+
+func TestOutranks(t *testing.T) {
+	cases := []struct {
+		a, b Role
+		want bool
+	}{
+		{SuperAdmin, Admin, true},
+		{Admin, SuperAdmin, false},
+		{Admin, User, true},
+		{User, Admin, false},
+		{Admin, Admin, false},
+		{Role("bogus"), User, false},
+		{User, Role("bogus"), true},
+	}
+	for _, c := range cases {
+		if got := Outranks(c.a, c.b); got != c.want {
+			t.Errorf("Outranks(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
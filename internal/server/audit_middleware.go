@@ -0,0 +1,61 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/audit"
+	"lam-phuong-api/internal/logger"
+)
+
+// AuditMiddleware records a generic audit.Entry for every non-GET request
+// once the handler has run. Repositories that understand a resource's
+// field-level diff (location, jobtype) record their own, richer entries
+// directly through the same Recorder; this middleware is the catch-all that
+// guarantees every mutation leaves a trail even when no repository hook
+// fires (auth routes, admin actions, etc).
+func AuditMiddleware(recorder audit.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Request.Method == "GET" || recorder == nil {
+			return
+		}
+
+		ctx := c.Request.Context()
+		resourceType, resourceID := resourceFromRoute(c)
+
+		entry := audit.Entry{
+			Actor:        audit.ActorFromContext(ctx),
+			Action:       audit.ActionRequest,
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			RequestID:    audit.RequestIDFromContext(ctx),
+			SourceIP:     c.ClientIP(),
+		}
+
+		if err := recorder.Record(ctx, entry); err != nil {
+			log := logger.FromContext(ctx)
+			log.Warn().Err(err).Msg("failed to record audit log entry")
+		}
+	}
+}
+
+// resourceFromRoute derives a coarse resource type from the matched Gin
+// route pattern (e.g. "/api/locations/:slug" -> "locations") and resolves
+// the first path parameter, if any, to its actual request-time value.
+func resourceFromRoute(c *gin.Context) (resourceType, resourceID string) {
+	segments := strings.Split(strings.Trim(c.FullPath(), "/"), "/")
+	for _, segment := range segments {
+		if segment == "api" || strings.HasPrefix(segment, ":") {
+			continue
+		}
+		resourceType = segment
+	}
+
+	if len(c.Params) > 0 {
+		resourceID = c.Params[0].Value
+	}
+
+	return resourceType, resourceID
+}
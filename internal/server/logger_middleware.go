@@ -0,0 +1,57 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/audit"
+	"lam-phuong-api/internal/logger"
+)
+
+// LoggerMiddleware assigns each request a correlation ID (reusing an
+// inbound X-Request-ID if present), attaches a request-scoped logger to the
+// request context, and emits one access-log line per request carrying
+// method, path, user, role, status, bytes, and latency.
+func LoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Header("X-Request-ID", requestID)
+		c.Set("request_id", requestID)
+
+		reqLogger := logger.Base.With().
+			Str("request_id", requestID).
+			Str("method", c.Request.Method).
+			Str("path", c.Request.URL.Path).
+			Logger()
+		ctx := logger.WithContext(c.Request.Context(), reqLogger)
+		ctx = audit.WithRequestID(ctx, requestID)
+		c.Request = c.Request.WithContext(ctx)
+
+		start := time.Now()
+		c.Next()
+
+		userID, _ := c.Get("user_id")
+		role, _ := c.Get("user_role")
+
+		reqLogger.Info().
+			Interface("user_id", userID).
+			Interface("role", role).
+			Int("status", c.Writer.Status()).
+			Int("bytes", c.Writer.Size()).
+			Dur("latency", time.Since(start)).
+			Msg("request completed")
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
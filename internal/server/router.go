@@ -8,16 +8,49 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"lam-phuong-api/internal/audit"
+	"lam-phuong-api/internal/authserver"
+	"lam-phuong-api/internal/authz"
+	"lam-phuong-api/internal/config"
 	"lam-phuong-api/internal/email"
+	"lam-phuong-api/internal/job"
+	jobCategory "lam-phuong-api/internal/jobCategory"
+	jobType "lam-phuong-api/internal/jobType"
 	"lam-phuong-api/internal/location"
+	"lam-phuong-api/internal/ratelimit"
+	"lam-phuong-api/internal/replication"
 	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/role"
+	"lam-phuong-api/internal/sync"
 	"lam-phuong-api/internal/user"
 )
 
+// Rate limits applied by NewRouter. Authenticated routes are keyed by user
+// ID so one user's traffic can't exhaust another's quota; anonymous routes
+// are keyed by IP.
+const (
+	anonymousRateLimit      = 10
+	anonymousRateWindow     = time.Minute
+	authenticatedRateLimit  = 120
+	authenticatedRateWindow = time.Minute
+)
+
 // NewRouter constructs a Gin engine configured with middleware and routes.
-func NewRouter(locationHandler *location.Handler, userHandler *user.Handler, emailHandler *email.Handler, jwtSecret string) *gin.Engine {
+func NewRouter(locationHandler *location.Handler, jobCategoryHandler *jobCategory.Handler, jobTypeHandler *jobType.Handler, userHandler *user.Handler, emailHandler *email.Handler, emailQueueHandler *email.QueueHandler, jobHandler *job.Handler, syncHandler *sync.Handler, auditHandler *audit.Handler, configHandler *config.Handler, authzHandler *authz.Handler, replicationHandler *replication.Handler, authServerHandler *authserver.Handler, jwtSecret string) *gin.Engine {
 	router := gin.Default()
 
+	// OAuth2/OIDC authorization server endpoints (/oauth/*,
+	// /.well-known/openid-configuration) live at the application root, same
+	// as /health and /swagger below, not under /api.
+	authServerHandler.RegisterRoutes(router)
+
+	// Assign a correlation ID and emit a structured access-log line for
+	// every request.
+	router.Use(LoggerMiddleware())
+
+	// Record every mutating request to the audit log, once the handler has run.
+	router.Use(AuditMiddleware(auditHandler.Recorder()))
+
 	// Configure CORS middleware
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // Allow all origins for development
@@ -36,27 +69,49 @@ func NewRouter(locationHandler *location.Handler, userHandler *user.Handler, ema
 		}, "Service is healthy")
 	})
 
+	rateLimitStore := ratelimit.NewInMemoryStore()
+
 	api := router.Group("/api")
 	{
-		// Auth routes (public)
-		userHandler.RegisterRoutes(api)
-
-		// Email test route (public)
-		api.POST("/email/test", func(c *gin.Context) {
-			if emailHandler == nil {
-				response.InternalError(c, "Email service is not configured. Please set EMAIL_CLIENT_ID, EMAIL_CLIENT_SECRET, and EMAIL_REFRESH_TOKEN environment variables.")
-				return
-			}
-			emailHandler.SendTestEmail(c)
-		})
+		// Public routes, rate limited by IP since there's no authenticated
+		// user to key on yet.
+		public := api.Group("")
+		public.Use(ratelimit.Middleware(ratelimit.Options{
+			Store:  rateLimitStore,
+			Limit:  anonymousRateLimit,
+			Window: anonymousRateWindow,
+			Key:    ratelimit.ByIP,
+		}))
+		{
+			// Auth routes (public)
+			userHandler.RegisterRoutes(public)
+
+			// Email test route (public)
+			public.POST("/email/test", func(c *gin.Context) {
+				if emailHandler == nil {
+					response.InternalError(c, "Email service is not configured. Please set EMAIL_CLIENT_ID, EMAIL_CLIENT_SECRET, and EMAIL_REFRESH_TOKEN environment variables.")
+					return
+				}
+				emailHandler.SendTestEmail(c)
+			})
+		}
 
 		// Protected routes (require authentication)
 		protected := api.Group("")
 		protected.Use(user.AuthMiddleware(jwtSecret))
+		protected.Use(ratelimit.Middleware(ratelimit.Options{
+			Store:  rateLimitStore,
+			Limit:  authenticatedRateLimit,
+			Window: authenticatedRateWindow,
+			Key:    ratelimit.ByUserID,
+		}))
 		{
 			// User password change route (authenticated users - own password only)
 			protected.POST("/auth/change-password", userHandler.ChangePassword)
 
+			// Refresh-token session management (authenticated users)
+			userHandler.RegisterProtectedRoutes(protected)
+
 			// User password change by ID (Super Admin can change any, others can only change own)
 			protected.POST("/users/:id/change-password", userHandler.ChangeUserPassword)
 
@@ -68,17 +123,78 @@ func NewRouter(locationHandler *location.Handler, userHandler *user.Handler, ema
 				adminRoutes.POST("/users", userHandler.CreateUser)
 				adminRoutes.DELETE("/users/:id", userHandler.DeleteUser)
 				adminRoutes.POST("/users/:id/toggle-status", userHandler.ToggleUserStatus)
+				adminRoutes.POST("/users/:id/revoke-sessions", userHandler.RevokeUserSessionsHandler)
+
+				// Runtime config admin API (admin only)
+				configHandler.RegisterRoutes(adminRoutes)
+
+				// RBAC permission matrix inspection (admin only)
+				authzHandler.RegisterRoutes(adminRoutes)
+
+				// Outbound email queue metrics (admin only)
+				if emailQueueHandler != nil {
+					emailQueueHandler.RegisterRoutes(adminRoutes)
+				}
 			}
 
-			// User update routes (super admin only)
+			// Role inspection/assignment (fine-grained, outranking-checked -
+			// see role.RequirePermission/role.Outranks - rather than the flat
+			// RequireAdmin gate above).
+			roleRoutes := protected.Group("")
+			roleRoutes.Use(role.RequirePermission(role.PermManageUsers))
+			{
+				roleRoutes.GET("/users/:id/role", userHandler.GetUserRole)
+				roleRoutes.PUT("/users/:id/role", userHandler.UpdateUserRole)
+			}
+
+			// User update routes (super admin only). Gated on both the flat
+			// role check and role.PermManageSystem - the one Permissions bit
+			// that distinguishes SuperAdmin from Admin - so the permission
+			// actually backs the access it documents rather than sitting
+			// unused.
 			superAdminRoutes := protected.Group("")
 			superAdminRoutes.Use(user.RequireRole(user.RoleSuperAdmin))
+			superAdminRoutes.Use(role.RequirePermission(role.PermManageSystem))
 			{
 				superAdminRoutes.PUT("/users/:id", userHandler.UpdateUser)
+
+				// Cache replication policy admin (super admin only)
+				syncHandler.RegisterRoutes(superAdminRoutes)
+
+				// Secondary-datastore replication policy admin (super admin only)
+				replicationHandler.RegisterRoutes(superAdminRoutes)
+			}
+
+			// Audit log querying (fine-grained - see role.RequirePermission
+			// above - rather than the flat superAdminRoutes gate the other
+			// routes in this block use).
+			auditRoutes := protected.Group("")
+			auditRoutes.Use(role.RequirePermission(role.PermViewAudit))
+			{
+				auditHandler.RegisterRoutes(auditRoutes)
+			}
+
+			// Job type routes (fine-grained - gated on PermManageJobs rather
+			// than a flat role check).
+			jobTypeRoutes := protected.Group("")
+			jobTypeRoutes.Use(role.RequirePermission(role.PermManageJobs))
+			{
+				jobTypeHandler.RegisterRoutes(jobTypeRoutes)
+			}
+
+			// Job category routes (fine-grained - gated on PermManageCategories
+			// rather than a flat role check).
+			jobCategoryRoutes := protected.Group("")
+			jobCategoryRoutes.Use(role.RequirePermission(role.PermManageCategories))
+			{
+				jobCategoryHandler.RegisterRoutes(jobCategoryRoutes)
 			}
 
 			// Location routes (authenticated users)
 			locationHandler.RegisterRoutes(protected)
+
+			// Async job status polling (authenticated users)
+			jobHandler.RegisterRoutes(protected)
 		}
 	}
 
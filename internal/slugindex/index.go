@@ -0,0 +1,115 @@
+// Package slugindex maintains a shared, in-memory slug-uniqueness index for
+// resources (jobcategory, jobtype, location, ...) that generate a slug from a
+// name and must guarantee it is unique before writing to Airtable. It exists
+// to replace each resource package's own ensureUniqueSlug, which recomputed
+// uniqueness with a full repo.List call on every create.
+package slugindex
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// reservationTTL bounds how long a Reserve'd slug is held before it is
+// considered abandoned (e.g. the caller crashed before calling release).
+const reservationTTL = 30 * time.Second
+
+// ListFunc loads every known slug for a table, keyed by slug with the
+// resource's record ID as the value. It is called once at startup via Warmup
+// and again on every reconciliation tick.
+type ListFunc func() map[string]string
+
+// ReleaseFunc releases a reservation obtained from Reserve. Callers should
+// defer it immediately after a successful Reserve.
+type ReleaseFunc func()
+
+// Index is a mutex-protected map[table]map[slug]recordID, plus a parallel
+// map of short-lived slug reservations used to prevent two concurrent
+// creates from picking the same slug before either has landed in Airtable.
+type Index struct {
+	mu           sync.Mutex
+	slugs        map[string]map[string]string
+	reservations map[string]map[string]time.Time
+}
+
+// New creates an empty Index. Call Warmup for each table before serving
+// traffic so Reserve has an accurate picture of what slugs already exist.
+func New() *Index {
+	return &Index{
+		slugs:        make(map[string]map[string]string),
+		reservations: make(map[string]map[string]time.Time),
+	}
+}
+
+// Warmup replaces a table's known slugs wholesale, discarding anything
+// previously cached for it. Used at startup and by Reconciler.
+func (idx *Index) Warmup(table string, list ListFunc) {
+	fresh := list()
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.slugs[table] = fresh
+}
+
+// Set write-through updates the index after a successful Create, so the new
+// slug is visible to the next Reserve without waiting for reconciliation.
+func (idx *Index) Set(table, slug, recordID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.slugs[table] == nil {
+		idx.slugs[table] = make(map[string]string)
+	}
+	idx.slugs[table][slug] = recordID
+}
+
+// Delete write-through updates the index after a successful DeleteBySlug.
+func (idx *Index) Delete(table, slug string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.slugs[table], slug)
+}
+
+// takenLocked reports whether slug is either a known record or currently
+// reserved by another in-flight create. Callers must hold idx.mu.
+func (idx *Index) takenLocked(table, slug string) bool {
+	if _, exists := idx.slugs[table][slug]; exists {
+		return true
+	}
+
+	reservedUntil, reserved := idx.reservations[table][slug]
+	return reserved && time.Now().Before(reservedUntil)
+}
+
+// Reserve picks the first of base, base-1, base-2, ... that is neither a
+// known slug nor already reserved, reserves it for reservationTTL, and
+// returns it along with a ReleaseFunc the caller must invoke once the slug
+// either lands in Airtable (followed by Set) or the create is abandoned.
+func (idx *Index) Reserve(table, base string) (string, ReleaseFunc) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	candidate := base
+	if idx.takenLocked(table, candidate) {
+		for i := 1; ; i++ {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+			if !idx.takenLocked(table, candidate) {
+				break
+			}
+		}
+	}
+
+	if idx.reservations[table] == nil {
+		idx.reservations[table] = make(map[string]time.Time)
+	}
+	idx.reservations[table][candidate] = time.Now().Add(reservationTTL)
+
+	release := func() {
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+		delete(idx.reservations[table], candidate)
+	}
+
+	return candidate, release
+}
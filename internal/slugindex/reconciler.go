@@ -0,0 +1,64 @@
+package slugindex
+
+import "time"
+
+// table pairs a table name with the ListFunc that can re-warm it.
+type table struct {
+	name string
+	list ListFunc
+}
+
+// Reconciler periodically re-warms an Index from each registered table's
+// ListFunc, so slugs created or deleted by some other process (a direct
+// Airtable edit, a restored backup) eventually become visible again even
+// without a matching Set/Delete write-through call.
+type Reconciler struct {
+	idx      *Index
+	interval time.Duration
+	tables   []table
+	stop     chan struct{}
+}
+
+// NewReconciler creates a Reconciler that re-warms idx on the given
+// interval. Call RegisterTable for each table before Start.
+func NewReconciler(idx *Index, interval time.Duration) *Reconciler {
+	return &Reconciler{
+		idx:      idx,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// RegisterTable adds a table to re-warm on every reconciliation tick. Must
+// be called before Start.
+func (r *Reconciler) RegisterTable(name string, list ListFunc) {
+	r.tables = append(r.tables, table{name: name, list: list})
+}
+
+// Start runs the reconciliation loop in a background goroutine until Stop
+// is called.
+func (r *Reconciler) Start() {
+	go r.run()
+}
+
+func (r *Reconciler) run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, t := range r.tables {
+				r.idx.Warmup(t.name, t.list)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Stop ends the reconciliation loop. It does not block for the in-flight
+// tick, if any, to finish.
+func (r *Reconciler) Stop() {
+	close(r.stop)
+}
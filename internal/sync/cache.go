@@ -0,0 +1,66 @@
+package sync
+
+import "sync"
+
+// Cache holds the most recently synced records for one table, keyed by
+// Airtable record ID. Repositories read through it instead of calling
+// Airtable on every List/Get/GetBySlug.
+type Cache struct {
+	mu   sync.RWMutex
+	data map[string]interface{}
+}
+
+// NewCache creates an empty Cache.
+func NewCache() *Cache {
+	return &Cache{data: make(map[string]interface{})}
+}
+
+// Set stores or overwrites a single record (used by the write-through path
+// after Create/Update).
+func (c *Cache) Set(id string, record interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[id] = record
+}
+
+// Delete removes a single record (used by the write-through path after
+// DeleteBySlug).
+func (c *Cache) Delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, id)
+}
+
+// Replace swaps the entire cache contents, used by a full refresh.
+func (c *Cache) Replace(records map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data = records
+}
+
+// All returns every cached record in no particular order.
+func (c *Cache) All() []interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]interface{}, 0, len(c.data))
+	for _, record := range c.data {
+		out = append(out, record)
+	}
+	return out
+}
+
+// Get retrieves a single cached record by ID.
+func (c *Cache) Get(id string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	record, ok := c.data[id]
+	return record, ok
+}
+
+// Len reports how many records are currently cached.
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.data)
+}
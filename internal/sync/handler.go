@@ -0,0 +1,112 @@
+package sync
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+)
+
+// Handler exposes admin endpoints for inspecting and controlling
+// replication policies. Callers should guard RegisterRoutes' group with
+// user.RequireRole(user.RoleSuperAdmin).
+type Handler struct {
+	runner *Runner
+}
+
+// NewHandler creates a handler backed by the given Runner.
+func NewHandler(runner *Runner) *Handler {
+	return &Handler{runner: runner}
+}
+
+// RegisterRoutes attaches sync routes to the supplied router group.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/sync/policies", h.ListPolicies)
+	router.PUT("/sync/policies", h.UpdatePolicy)
+	router.POST("/sync/policies/:table/trigger", h.TriggerPolicy)
+}
+
+// ListPolicies godoc
+// @Summary      List replication policies
+// @Description  Get the cron schedule and last-run time for each synced table (requires super admin role)
+// @Tags         sync
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "Policies retrieved successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Router       /sync/policies [get]
+func (h *Handler) ListPolicies(c *gin.Context) {
+	response.Success(c, http.StatusOK, h.runner.Policies(), "Policies retrieved successfully")
+}
+
+type updatePolicyPayload struct {
+	Table    string `json:"table" binding:"required"`
+	CronExpr string `json:"cron_expr" binding:"required"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// UpdatePolicy godoc
+// @Summary      Update a replication policy
+// @Description  Change a table's cron expression and enabled flag (requires super admin role)
+// @Tags         sync
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        policy  body      updatePolicyPayload  true  "Policy update"
+// @Success      200     {object}  response.Response  "Policy updated successfully"
+// @Failure      400     {object}  response.ErrorResponse  "Validation error"
+// @Failure      401     {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  response.ErrorResponse  "Forbidden"
+// @Failure      404     {object}  response.ErrorResponse  "Unknown table"
+// @Router       /sync/policies [put]
+func (h *Handler) UpdatePolicy(c *gin.Context) {
+	var payload updatePolicyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.runner.SetPolicy(payload.Table, payload.CronExpr, payload.Enabled)
+	if err != nil {
+		response.NotFound(c, "Replication policy")
+		return
+	}
+
+	response.Success(c, http.StatusOK, policy, "Policy updated successfully")
+}
+
+// TriggerPolicy godoc
+// @Summary      Force an immediate sync
+// @Description  Run a table's replication immediately, outside its cron schedule (requires super admin role)
+// @Tags         sync
+// @Produce      json
+// @Security     BearerAuth
+// @Param        table  path      string  true  "Table name"
+// @Success      200    {object}  response.Response  "Sync triggered successfully"
+// @Failure      401    {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403    {object}  response.ErrorResponse  "Forbidden"
+// @Failure      404    {object}  response.ErrorResponse  "Unknown table"
+// @Failure      500    {object}  response.ErrorResponse  "Refresh failed"
+// @Router       /sync/policies/{table}/trigger [post]
+func (h *Handler) TriggerPolicy(c *gin.Context) {
+	table := c.Param("table")
+	known := false
+	for _, policy := range h.runner.Policies() {
+		if policy.Table == table {
+			known = true
+			break
+		}
+	}
+	if !known {
+		response.NotFound(c, "Replication policy")
+		return
+	}
+	if err := h.runner.TriggerNow(table); err != nil {
+		response.InternalError(c, "Failed to trigger sync: "+err.Error())
+		return
+	}
+	response.SuccessNoContent(c, "Sync triggered successfully")
+}
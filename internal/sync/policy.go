@@ -0,0 +1,13 @@
+package sync
+
+import "time"
+
+// ReplicationPolicy describes how a single Airtable table is mirrored into
+// the in-memory Cache: its refresh schedule and whether it's active.
+// Loosely modeled on Harbor's replication_policy.
+type ReplicationPolicy struct {
+	Table    string    `json:"table"`
+	CronExpr string    `json:"cron_expr"`
+	Enabled  bool      `json:"enabled"`
+	LastRun  time.Time `json:"last_run,omitempty"`
+}
@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// RefreshFunc re-populates a table's Cache from Airtable.
+type RefreshFunc func(ctx context.Context) error
+
+// Runner owns one ReplicationPolicy and RefreshFunc per table and drives
+// scheduled and on-demand refreshes via robfig/cron.
+type Runner struct {
+	mu       sync.RWMutex
+	cron     *cron.Cron
+	policies map[string]ReplicationPolicy
+	refresh  map[string]RefreshFunc
+	entryIDs map[string]cron.EntryID
+}
+
+// NewRunner creates a Runner with its own cron scheduler. Call Start to
+// begin running scheduled refreshes.
+func NewRunner() *Runner {
+	return &Runner{
+		cron:     cron.New(),
+		policies: make(map[string]ReplicationPolicy),
+		refresh:  make(map[string]RefreshFunc),
+		entryIDs: make(map[string]cron.EntryID),
+	}
+}
+
+// Register adds a table's policy and refresh function, scheduling it with
+// cron immediately if the policy is enabled.
+func (r *Runner) Register(policy ReplicationPolicy, refresh RefreshFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.policies[policy.Table] = policy
+	r.refresh[policy.Table] = refresh
+
+	if policy.Enabled {
+		return r.scheduleLocked(policy)
+	}
+	return nil
+}
+
+func (r *Runner) scheduleLocked(policy ReplicationPolicy) error {
+	entryID, err := r.cron.AddFunc(policy.CronExpr, func() {
+		if err := r.TriggerNow(policy.Table); err != nil {
+			log.Printf("sync: scheduled refresh of %s failed: %v", policy.Table, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %q for table %s: %w", policy.CronExpr, policy.Table, err)
+	}
+	r.entryIDs[policy.Table] = entryID
+	return nil
+}
+
+// Start begins the cron scheduler in the background.
+func (r *Runner) Start() { r.cron.Start() }
+
+// Stop halts the cron scheduler, waiting for in-flight jobs to finish.
+func (r *Runner) Stop() { <-r.cron.Stop().Done() }
+
+// Policies returns a snapshot of all registered policies.
+func (r *Runner) Policies() []ReplicationPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]ReplicationPolicy, 0, len(r.policies))
+	for _, policy := range r.policies {
+		out = append(out, policy)
+	}
+	return out
+}
+
+// SetPolicy updates a table's cron expression and enabled flag, rescheduling
+// it with the cron runner.
+func (r *Runner) SetPolicy(table, cronExpr string, enabled bool) (ReplicationPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	policy, ok := r.policies[table]
+	if !ok {
+		return ReplicationPolicy{}, fmt.Errorf("unknown table %q", table)
+	}
+
+	if entryID, scheduled := r.entryIDs[table]; scheduled {
+		r.cron.Remove(entryID)
+		delete(r.entryIDs, table)
+	}
+
+	policy.CronExpr = cronExpr
+	policy.Enabled = enabled
+	r.policies[table] = policy
+
+	if enabled {
+		if err := r.scheduleLocked(policy); err != nil {
+			return ReplicationPolicy{}, err
+		}
+	}
+	return policy, nil
+}
+
+// TriggerNow runs a table's refresh immediately, outside its cron schedule.
+func (r *Runner) TriggerNow(table string) error {
+	r.mu.RLock()
+	refresh, ok := r.refresh[table]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown table %q", table)
+	}
+
+	if err := refresh(context.Background()); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	policy := r.policies[table]
+	policy.LastRun = time.Now()
+	r.policies[table] = policy
+	r.mu.Unlock()
+	return nil
+}
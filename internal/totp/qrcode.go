@@ -0,0 +1,12 @@
+package totp
+
+import qrcode "github.com/skip2/go-qrcode"
+
+// qrCodeSize is the side length, in pixels, of the generated PNG.
+const qrCodeSize = 256
+
+// GenerateQRCodePNG renders otpauthURL as a PNG QR code so clients can
+// display it for an authenticator app to scan during enrollment.
+func GenerateQRCodePNG(otpauthURL string) ([]byte, error) {
+	return qrcode.Encode(otpauthURL, qrcode.Medium, qrCodeSize)
+}
@@ -0,0 +1,88 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateAtRoundTrip(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret returned an error: %v", err)
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	code, err := GenerateCode(secret, now)
+	if err != nil {
+		t.Fatalf("GenerateCode returned an error: %v", err)
+	}
+
+	if !ValidateAt(secret, code, now) {
+		t.Error("ValidateAt rejected a code generated for the same secret and time")
+	}
+	if ValidateAt(secret, "000000", now) {
+		t.Error("ValidateAt accepted an unrelated code")
+	}
+}
+
+func TestValidateAtToleratesClockSkew(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret returned an error: %v", err)
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	code, err := GenerateCode(secret, now)
+	if err != nil {
+		t.Fatalf("GenerateCode returned an error: %v", err)
+	}
+
+	if !ValidateAt(secret, code, now.Add(period)) {
+		t.Error("ValidateAt rejected a code one period in the future; skewSteps should tolerate this")
+	}
+	if !ValidateAt(secret, code, now.Add(-period)) {
+		t.Error("ValidateAt rejected a code one period in the past; skewSteps should tolerate this")
+	}
+	if ValidateAt(secret, code, now.Add(2*(skewSteps+1)*period)) {
+		t.Error("ValidateAt accepted a code far outside the skew window")
+	}
+}
+
+func TestDecodeSecretAcceptsUnpaddedLowercase(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret returned an error: %v", err)
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	wantCode, err := GenerateCode(secret, now)
+	if err != nil {
+		t.Fatalf("GenerateCode returned an error: %v", err)
+	}
+
+	messy := trimPadding(toLower(secret))
+	gotCode, err := generateCodeForStep(messy, counterAt(now))
+	if err != nil {
+		t.Fatalf("generateCodeForStep returned an error for a messy secret: %v", err)
+	}
+	if gotCode != wantCode {
+		t.Errorf("generateCodeForStep(%q) = %q, want %q (same secret, just unpadded/lowercased)", messy, gotCode, wantCode)
+	}
+}
+
+func trimPadding(s string) string {
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func toLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c - 'A' + 'a'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,122 @@
+// Package types holds small, dependency-light value types shared across
+// packages that would otherwise each re-implement their own normalization
+// and validation (starting with Email).
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Email is a normalized, validated email address: local and domain parts
+// lowercased, surrounding whitespace trimmed, and the domain converted to
+// its ASCII (Punycode) form so internationalized domains compare equal
+// regardless of which representation a caller typed. Two Emails built from
+// NewEmail are safe to compare with ==.
+type Email string
+
+// NewEmail parses and normalizes raw into an Email. It rejects addresses
+// without exactly one "@", an empty local part, or a domain that fails
+// IDNA conversion.
+func NewEmail(raw string) (Email, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", fmt.Errorf("email: address is empty")
+	}
+
+	at := strings.LastIndex(trimmed, "@")
+	if at <= 0 || at == len(trimmed)-1 || strings.Count(trimmed, "@") != 1 {
+		return "", fmt.Errorf("email: %q is not a valid address", raw)
+	}
+
+	local := strings.ToLower(trimmed[:at])
+	domain, err := idna.Lookup.ToASCII(strings.ToLower(trimmed[at+1:]))
+	if err != nil {
+		return "", fmt.Errorf("email: invalid domain in %q: %w", raw, err)
+	}
+
+	return Email(local + "@" + domain), nil
+}
+
+// String returns the normalized address.
+func (e Email) String() string {
+	return string(e)
+}
+
+// MarshalJSON implements json.Marshaler, encoding Email as a plain JSON
+// string.
+func (e Email) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It normalizes the decoded
+// string via NewEmail, so an Email field is always stored normalized
+// regardless of how the caller capitalized their input.
+func (e *Email) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == "" {
+		*e = ""
+		return nil
+	}
+	normalized, err := NewEmail(raw)
+	if err != nil {
+		return err
+	}
+	*e = normalized
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e Email) MarshalText() ([]byte, error) {
+	return []byte(e), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, normalizing the same
+// way UnmarshalJSON does.
+func (e *Email) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*e = ""
+		return nil
+	}
+	normalized, err := NewEmail(string(text))
+	if err != nil {
+		return err
+	}
+	*e = normalized
+	return nil
+}
+
+// Scan implements sql.Scanner, so Email can be read directly from a
+// database column.
+func (e *Email) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*e = ""
+		return nil
+	case string:
+		*e = Email(v)
+		return nil
+	case []byte:
+		*e = Email(v)
+		return nil
+	default:
+		return fmt.Errorf("email: cannot scan %T into Email", src)
+	}
+}
+
+// Value implements driver.Valuer, so Email can be written directly to a
+// database column.
+func (e Email) Value() (driver.Value, error) {
+	if e == "" {
+		return nil, nil
+	}
+	return string(e), nil
+}
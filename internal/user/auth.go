@@ -1,11 +1,16 @@
 package user
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"lam-phuong-api/internal/audit"
+	"lam-phuong-api/internal/logger"
 	"lam-phuong-api/internal/response"
 )
 
@@ -31,7 +36,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		tokenString := parts[1]
 
 		// Validate token
-		claims, err := ValidateToken(tokenString, jwtSecret)
+		claims, err := validateAccessToken(tokenString, jwtSecret)
 		if err != nil {
 			// Check if token is expired (jwt/v5 returns errors with "expired" in the message)
 			errMsg := strings.ToLower(err.Error())
@@ -49,10 +54,42 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		c.Set("user_email", claims.Email)
 		c.Set("user_role", claims.Role)
 
+		// Carry the authenticated actor through context.Context so
+		// repositories can attribute audit log entries without needing a
+		// *gin.Context, and enrich the request-scoped logger so every log
+		// line a repository emits for this request is already tagged with
+		// who made it.
+		ctx := audit.WithActor(c.Request.Context(), audit.Actor{
+			UserID: claims.UserID,
+			Role:   claims.Role,
+		})
+		enrichedLog := logger.FromContext(ctx).With().
+			Str("user_id", claims.UserID).
+			Str("user_email", string(claims.Email)).
+			Logger()
+		c.Request = c.Request.WithContext(logger.WithContext(ctx, enrichedLog))
+
 		c.Next()
 	}
 }
 
+// validateAccessToken accepts both of the token formats AuthMiddleware
+// supports: the locally-signed HS256 tokens ValidateToken has always
+// checked, and RS256 tokens from internal/authserver, routed to the
+// registered JWKSVerifier by inspecting the unverified "alg" header. With
+// no verifier registered, every token is treated as HS256, same as before
+// RS256 support existed.
+func validateAccessToken(tokenString, jwtSecret string) (*Claims, error) {
+	if jwksVerifier != nil {
+		if token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{}); err == nil {
+			if _, isRSA := token.Method.(*jwt.SigningMethodRSA); isRSA {
+				return jwksVerifier.Verify(tokenString)
+			}
+		}
+	}
+	return ValidateToken(tokenString, jwtSecret)
+}
+
 // LoginRequest represents the login request payload
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -69,9 +106,8 @@ func (h *Handler) Login(c *gin.Context, jwtSecret string, tokenExpiry time.Durat
 		return
 	}
 
-	// Get user by email
-	user, ok := h.repo.GetByEmail(req.Email)
-	if !ok {
+	user, err := h.authenticate(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
 		response.InvalidAuth(c, "Invalid email or password")
 		return
 	}
@@ -86,14 +122,24 @@ func (h *Handler) Login(c *gin.Context, jwtSecret string, tokenExpiry time.Durat
 		return
 	}
 
-	// Verify password
-	if !CheckPassword(user.Password, req.Password) {
-		response.InvalidAuth(c, "Invalid email or password")
+	// If the user has enrolled in 2FA, don't issue a token yet: hand back a
+	// short-lived signed challenge that POST /auth/2fa/login must exchange
+	// for one, along with the TOTP code.
+	if user.TOTPEnabled {
+		mfaToken, err := h.signMFAChallenge(user.ID)
+		if err != nil {
+			response.InternalError(c, "Failed to start 2FA challenge")
+			return
+		}
+		response.Success(c, http.StatusOK, gin.H{
+			"mfa_required": true,
+			"mfa_token":    mfaToken,
+		}, "2FA code required")
 		return
 	}
 
 	// Generate JWT token
-	token, err := GenerateToken(user, jwtSecret, tokenExpiry)
+	token, jti, err := GenerateToken(user, jwtSecret, tokenExpiry)
 	if err != nil {
 		response.InternalError(c, "Failed to generate token")
 		return
@@ -102,12 +148,43 @@ func (h *Handler) Login(c *gin.Context, jwtSecret string, tokenExpiry time.Durat
 	// Remove password from user object
 	user.Password = ""
 
+	// Issue a refresh token alongside the access token when the subsystem
+	// is configured; a failure here shouldn't fail the login itself.
+	refreshToken, err := h.issueRefreshToken(c.Request.Context(), c, user.ID, jti, time.Now().Add(tokenExpiry))
+	if err != nil {
+		log.Printf("Failed to issue refresh token: %v", err)
+	}
+
 	// Return token response
 	tokenResp := TokenResponse{
-		AccessToken: token,
-		TokenType:   "Bearer",
-		ExpiresIn:   int64(tokenExpiry.Seconds()),
-		User:        user,
+		AccessToken:  token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokenExpiry.Seconds()),
+		User:         user,
+		RefreshToken: refreshToken,
+	}
+	if refreshToken != "" {
+		tokenResp.RefreshExpiresIn = int64(refreshTokenTTL.Seconds())
 	}
 	response.Success(c, http.StatusOK, tokenResp, "Login successful")
 }
+
+// authenticate tries each configured Authenticator in order, returning the
+// first successful result. With no authenticators configured it falls back
+// to verifying the password against the local Repository directly, so
+// existing deployments keep working without setting AUTH_PROVIDERS.
+func (h *Handler) authenticate(ctx context.Context, email, password string) (User, error) {
+	if len(h.authenticators) == 0 {
+		return NewLocalAuthenticator(h.repo).Login(ctx, email, password)
+	}
+
+	var lastErr error
+	for _, authenticator := range h.authenticators {
+		user, err := authenticator.Login(ctx, email, password)
+		if err == nil {
+			return user, nil
+		}
+		lastErr = err
+	}
+	return User{}, lastErr
+}
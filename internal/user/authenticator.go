@@ -0,0 +1,217 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"lam-phuong-api/internal/types"
+)
+
+// AuthedUser is the canonical identity attached to the Gin context by
+// AuthMiddleware, regardless of which Authenticator resolved the request.
+// Downstream handlers read this instead of caring which backend authed them.
+type AuthedUser struct {
+	ID       string
+	Email    string
+	Role     string
+	Provider string
+}
+
+// Authenticator verifies credentials against a single backend and returns
+// the resulting User. Handler.Login tries the Authenticators set via
+// SetAuthenticators in order until one succeeds.
+type Authenticator interface {
+	// Name identifies the backend (e.g. "local", "ldap", "jwt") and is
+	// surfaced via GET /api/auth/providers.
+	Name() string
+	Login(ctx context.Context, username, password string) (User, error)
+}
+
+// LocalAuthenticator verifies credentials against the existing
+// Airtable-backed Repository using the stored password hash (argon2id, or a
+// legacy bcrypt hash; see CheckPassword). This is the pre-existing login
+// behavior, extracted so it can sit alongside other backends.
+type LocalAuthenticator struct {
+	repo Repository
+}
+
+// NewLocalAuthenticator creates an Authenticator backed by repo.
+func NewLocalAuthenticator(repo Repository) *LocalAuthenticator {
+	return &LocalAuthenticator{repo: repo}
+}
+
+// Name identifies this backend as "local".
+func (a *LocalAuthenticator) Name() string { return "local" }
+
+// Login verifies username/password against the local Repository. A
+// successful verification against a legacy bcrypt hash transparently
+// re-hashes the password with argon2id and persists it, so the database
+// migrates gradually with no separate migration step.
+func (a *LocalAuthenticator) Login(ctx context.Context, username, password string) (User, error) {
+	email, err := types.NewEmail(username)
+	if err != nil {
+		return User{}, fmt.Errorf("invalid email or password")
+	}
+
+	u, ok := a.repo.GetByEmail(email)
+	if !ok {
+		return User{}, fmt.Errorf("invalid email or password")
+	}
+	if !CheckPassword(u.Password, password) {
+		return User{}, fmt.Errorf("invalid email or password")
+	}
+
+	if needsRehash(u.Password) {
+		if newHash, err := HashPassword(password); err == nil {
+			u.Password = newHash
+			if updated, err := a.repo.Update(ctx, u.ID, u); err == nil {
+				u = updated
+			}
+		}
+	}
+
+	return u, nil
+}
+
+var _ Authenticator = (*LocalAuthenticator)(nil)
+
+// LDAPRoleMapping maps an LDAP group DN to one of the RoleSuperAdmin,
+// RoleAdmin, or RoleUser constants.
+type LDAPRoleMapping map[string]string
+
+// LDAPAuthenticator verifies credentials by binding to an LDAP directory
+// and upserts the resulting identity into Repository on first login,
+// mapping LDAP group membership to a local role via RoleMapping.
+type LDAPAuthenticator struct {
+	repo             Repository
+	addr             string
+	bindDN           string
+	bindPass         string
+	userBaseDN       string
+	roleMap          LDAPRoleMapping
+	selfRegistration bool
+}
+
+// NewLDAPAuthenticator creates an Authenticator backed by the LDAP server at
+// addr (e.g. "ldaps://ldap.example.com:636"). bindDN/bindPass are used for
+// the initial search bind; userBaseDN scopes the user search; roleMap
+// translates LDAP group DNs found in memberOf to local roles. When
+// selfRegistration is false, a successful bind from someone with no existing
+// Airtable user row is rejected instead of provisioning one.
+func NewLDAPAuthenticator(repo Repository, addr, bindDN, bindPass, userBaseDN string, roleMap LDAPRoleMapping, selfRegistration bool) *LDAPAuthenticator {
+	return &LDAPAuthenticator{
+		repo:             repo,
+		addr:             addr,
+		bindDN:           bindDN,
+		bindPass:         bindPass,
+		userBaseDN:       userBaseDN,
+		roleMap:          roleMap,
+		selfRegistration: selfRegistration,
+	}
+}
+
+// Name identifies this backend as "ldap".
+func (a *LDAPAuthenticator) Name() string { return "ldap" }
+
+// Login binds as the service account, searches for the user by uid,
+// rebinds as that user to verify the password, then upserts a local User
+// record with a role derived from the user's LDAP group membership.
+func (a *LDAPAuthenticator) Login(ctx context.Context, username, password string) (User, error) {
+	conn, err := ldap.DialURL(a.addr)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(a.bindDN, a.bindPass); err != nil {
+		return User{}, fmt.Errorf("LDAP service bind failed: %w", err)
+	}
+
+	searchReq := ldap.NewSearchRequest(
+		a.userBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 1, 0, false,
+		fmt.Sprintf("(uid=%s)", ldap.EscapeFilter(username)),
+		[]string{"dn", "mail", "memberOf"},
+		nil,
+	)
+	result, err := conn.Search(searchReq)
+	if err != nil || len(result.Entries) != 1 {
+		return User{}, fmt.Errorf("invalid email or password")
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		return User{}, fmt.Errorf("invalid email or password")
+	}
+
+	rawEmail := entry.GetAttributeValue("mail")
+	if rawEmail == "" {
+		rawEmail = username
+	}
+	email, err := types.NewEmail(rawEmail)
+	if err != nil {
+		return User{}, fmt.Errorf("invalid email or password")
+	}
+
+	existing, found := a.repo.GetByEmail(email)
+	if !found && !a.selfRegistration {
+		return User{}, fmt.Errorf("no local account for %s and self-registration is disabled", email)
+	}
+
+	provisioned := User{
+		ID:     existing.ID,
+		Email:  email,
+		Role:   a.roleForGroups(entry.GetAttributeValues("memberOf")),
+		Status: StatusActive,
+	}
+	return a.repo.Upsert(ctx, provisioned)
+}
+
+func (a *LDAPAuthenticator) roleForGroups(groups []string) string {
+	for _, group := range groups {
+		if role, ok := a.roleMap[group]; ok {
+			return role
+		}
+	}
+	return RoleUser
+}
+
+var _ Authenticator = (*LDAPAuthenticator)(nil)
+
+// JWTAuthenticator accepts an upstream-issued JWT (passed as `password`) in
+// place of a local password, verifying it against the same HMAC secret used
+// to issue this service's own tokens, and provisions a local User record on
+// first sight so role checks can use RequireRole like any other user.
+type JWTAuthenticator struct {
+	repo      Repository
+	jwtSecret string
+}
+
+// NewJWTAuthenticator creates an Authenticator that trusts tokens signed
+// with jwtSecret.
+func NewJWTAuthenticator(repo Repository, jwtSecret string) *JWTAuthenticator {
+	return &JWTAuthenticator{repo: repo, jwtSecret: jwtSecret}
+}
+
+// Name identifies this backend as "jwt".
+func (a *JWTAuthenticator) Name() string { return "jwt" }
+
+// Login ignores username and treats password as the upstream bearer token.
+func (a *JWTAuthenticator) Login(ctx context.Context, username, password string) (User, error) {
+	claims, err := ValidateToken(password, a.jwtSecret)
+	if err != nil {
+		return User{}, fmt.Errorf("invalid upstream token: %w", err)
+	}
+
+	existing, _ := a.repo.GetByEmail(claims.Email)
+	provisioned := User{
+		ID:     existing.ID,
+		Email:  claims.Email,
+		Role:   claims.Role,
+		Status: StatusActive,
+	}
+	return a.repo.Upsert(ctx, provisioned)
+}
+
+var _ Authenticator = (*JWTAuthenticator)(nil)
@@ -5,7 +5,11 @@ import (
 	"lam-phuong-api/internal/response"
 )
 
-// RequireRole creates a middleware that requires the user to have one of the specified roles
+// RequireRole creates a middleware that requires the user to have one of the
+// specified roles. For finer-grained, per-resource checks (e.g. "can this
+// role create a location but not delete one"), prefer authz.RequirePermission
+// against a policy Table instead; RequireRole/RequireAdmin/RequireAnyRole stay
+// as-is for routes that only need a flat role check.
 func RequireRole(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get user role from context (set by AuthMiddleware)
@@ -1,44 +1,82 @@
 package user
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"lam-phuong-api/internal/ratelimit"
+	"lam-phuong-api/internal/refreshtoken"
 	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/revocation"
+	"lam-phuong-api/internal/role"
+	"lam-phuong-api/internal/types"
 
 	"github.com/gin-gonic/gin"
 )
 
+// defaultUserPageSize and maxUserPageSize bound ListUsers' page_size param.
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// passwordResetTokenTTL bounds how long a forgot-password token is valid.
+const passwordResetTokenTTL = time.Hour
+
+// passwordResetRateLimit caps forgot-password attempts per IP+email pair,
+// to slow down both enumeration and inbox-flooding abuse.
+const (
+	passwordResetRateLimitCount  = 3
+	passwordResetRateLimitWindow = 15 * time.Minute
+)
+
 // Handler exposes HTTP handlers for the user resource
 type Handler struct {
 	repo         Repository
 	jwtSecret    string
 	tokenExpiry  time.Duration
 	emailService interface {
-		SendVerificationEmail(toEmail, verificationToken, baseURL string) error
+		SendTemplate(ctx context.Context, name string, toEmail types.Email, data map[string]interface{}) error
 	}
-	baseURL string
+	baseURL              string
+	authenticators       []Authenticator
+	oauthProviders       map[string]OAuthProviderConfig
+	passwordResetLimiter ratelimit.Store
+	refreshTokens        refreshtoken.Repository
+	revocations          revocation.Store
 }
 
 // NewHandler creates a handler with the provided repository
 func NewHandler(repo Repository, jwtSecret string, tokenExpiry time.Duration) *Handler {
 	return &Handler{
-		repo:        repo,
-		jwtSecret:   jwtSecret,
-		tokenExpiry: tokenExpiry,
+		repo:                 repo,
+		jwtSecret:            jwtSecret,
+		tokenExpiry:          tokenExpiry,
+		passwordResetLimiter: ratelimit.NewInMemoryStore(),
 	}
 }
 
-// SetEmailService sets the email service and base URL for verification emails
+// SetEmailService sets the email service and base URL for verification and
+// password reset emails
 func (h *Handler) SetEmailService(emailService interface {
-	SendVerificationEmail(toEmail, verificationToken, baseURL string) error
+	SendTemplate(ctx context.Context, name string, toEmail types.Email, data map[string]interface{}) error
 }, baseURL string) {
 	h.emailService = emailService
 	h.baseURL = baseURL
 }
 
+// SetAuthenticators configures the ordered list of backends tried on login
+// (e.g. local, LDAP, JWT). Selected via the AUTH_PROVIDERS config value.
+// Without a call to this, Login falls back to local-only authentication.
+func (h *Handler) SetAuthenticators(authenticators []Authenticator) {
+	h.authenticators = authenticators
+}
+
 // RegisterRoutes attaches user routes to the supplied router group
 // Only registers public auth routes. Protected routes should be registered separately in router.go
 func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
@@ -46,6 +84,46 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	router.POST("/auth/register", h.RegisterHandler)
 	router.POST("/auth/login", h.LoginHandler)
 	router.GET("/auth/verify-email", h.VerifyEmailHandler)
+	router.GET("/auth/providers", h.ListAuthProviders)
+	router.GET("/auth/oauth/:provider/login", h.OAuthLogin)
+	router.GET("/auth/oauth/:provider/callback", h.OAuthCallback)
+	router.POST("/auth/forgot-password", h.ForgotPasswordHandler)
+	router.POST("/auth/reset-password", h.ResetPasswordHandler)
+	// password-reset/request and password-reset/confirm are aliases for the
+	// routes above, kept for callers that integrate against that naming.
+	router.POST("/auth/password-reset/request", h.ForgotPasswordHandler)
+	router.POST("/auth/password-reset/confirm", h.ResetPasswordHandler)
+	router.POST("/auth/refresh", h.RefreshTokenHandler)
+	router.POST("/auth/logout", h.LogoutHandler)
+	router.POST("/auth/2fa/login", h.MFALoginHandler)
+}
+
+// RegisterProtectedRoutes attaches user routes that require an authenticated
+// session. Mounted by router.go under its protected route group.
+func (h *Handler) RegisterProtectedRoutes(router *gin.RouterGroup) {
+	router.POST("/auth/logout-all", h.LogoutAllHandler)
+	router.GET("/auth/sessions", h.ListSessionsHandler)
+	router.POST("/auth/2fa/enroll", h.Enroll2FAHandler)
+	router.POST("/auth/2fa/verify", h.Verify2FAHandler)
+}
+
+// ListAuthProviders godoc
+// @Summary      List enabled authentication providers
+// @Description  Returns the names of the authentication backends enabled via AUTH_PROVIDERS, for the frontend login UI
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response  "Providers retrieved successfully"
+// @Router       /auth/providers [get]
+func (h *Handler) ListAuthProviders(c *gin.Context) {
+	names := make([]string, 0, len(h.authenticators))
+	for _, authenticator := range h.authenticators {
+		names = append(names, authenticator.Name())
+	}
+	if len(names) == 0 {
+		names = []string{"local"}
+	}
+	response.Success(c, http.StatusOK, gin.H{"providers": names}, "Providers retrieved successfully")
 }
 
 // Register godoc
@@ -69,8 +147,16 @@ func (h *Handler) RegisterHandler(c *gin.Context) {
 		return
 	}
 
+	email, err := types.NewEmail(req.Email)
+	if err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
 	// Check if user already exists
-	_, exists := h.repo.GetByEmail(req.Email)
+	_, exists := h.repo.GetByEmail(email)
 	if exists {
 		response.DuplicateEmail(c)
 		return
@@ -92,7 +178,7 @@ func (h *Handler) RegisterHandler(c *gin.Context) {
 
 	// Create user with default "User" role and pending status
 	user := User{
-		Email:                  req.Email,
+		Email:                  email,
 		Password:               hashedPassword,
 		Role:                   RoleUser,      // Always "User" role for public registration
 		Status:                 StatusPending, // Set to pending until email is verified
@@ -113,7 +199,8 @@ func (h *Handler) RegisterHandler(c *gin.Context) {
 
 	// Send verification email if email service is configured
 	if h.emailService != nil && h.baseURL != "" {
-		if err := h.emailService.SendVerificationEmail(created.Email, verificationToken, h.baseURL); err != nil {
+		data := map[string]interface{}{"BaseURL": h.baseURL, "Token": verificationToken}
+		if err := h.emailService.SendTemplate(c.Request.Context(), "verification", created.Email, data); err != nil {
 			// Log error but don't fail registration - email can be resent later
 			log.Printf("Failed to send verification email: %v", err)
 		}
@@ -180,7 +267,7 @@ func (h *Handler) VerifyEmailHandler(c *gin.Context) {
 
 // Login godoc
 // @Summary      User login
-// @Description  Authenticate user with email and password, returns JWT token. User must have verified their email address (status must be Active, not Pending).
+// @Description  Authenticate user with email and password, returns JWT token. User must have verified their email address (status must be Active, not Pending). If the account has 2FA enabled, this returns {"mfa_required": true, "mfa_token": ...} instead; complete the login via POST /auth/2fa/login.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -195,25 +282,119 @@ func (h *Handler) LoginHandler(c *gin.Context) {
 }
 
 // ListUsers godoc
-// @Summary      List all users
-// @Description  Get a list of all users (requires admin role)
+// @Summary      List users
+// @Description  Get a paginated, filterable list of users (requires admin role). Sets X-Total-Count and RFC 5988 Link headers for pagination.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        page       query     int     false  "Page number, starting at 1 (default 1)"
+// @Param        page_size  query     int     false  "Results per page, max 100 (default 20)"
+// @Param        email      query     string  false  "Filter by email substring"
+// @Param        role       query     string  false  "Filter by exact role"
+// @Param        status     query     string  false  "Filter by exact status"
+// @Param        sort       query     string  false  "Sort key: email or created_at, prefix with - for descending (default email)"
 // @Success      200  {object}  user.UsersResponseWrapper  "Users retrieved successfully"
+// @Failure      400  {object}  response.ErrorResponse  "Invalid pagination or sort parameter"
 // @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
 // @Failure      403  {object}  response.ErrorResponse  "Forbidden"
 // @Router       /users [get]
 func (h *Handler) ListUsers(c *gin.Context) {
-	users := h.repo.List()
+	page := 1
+	if raw := c.Query("page"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			response.ValidationError(c, "Invalid 'page', expected a positive integer", nil)
+			return
+		}
+		page = parsed
+	}
+
+	pageSize := defaultUserPageSize
+	if raw := c.Query("page_size"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			response.ValidationError(c, "Invalid 'page_size', expected a positive integer", nil)
+			return
+		}
+		pageSize = parsed
+	}
+	if pageSize > maxUserPageSize {
+		pageSize = maxUserPageSize
+	}
+
+	sortParam := c.DefaultQuery("sort", "email")
+	sortKey := strings.TrimPrefix(sortParam, "-")
+	validSort := false
+	for _, field := range SortableFields {
+		if sortKey == field {
+			validSort = true
+			break
+		}
+	}
+	if !validSort {
+		response.ValidationError(c, "Invalid 'sort' key", map[string]interface{}{
+			"valid_sort_keys": SortableFields,
+		})
+		return
+	}
+
+	filter := UserFilter{
+		Email:  c.Query("email"),
+		Role:   c.Query("role"),
+		Status: c.Query("status"),
+		Sort:   sortParam,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	}
+
+	users, total, err := h.repo.Search(c.Request.Context(), filter)
+	if err != nil {
+		response.InternalError(c, "Failed to list users: "+err.Error())
+		return
+	}
+
 	// Remove passwords from response
 	for i := range users {
 		users[i].Password = ""
 	}
+
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.Header("Link", buildUserListLinkHeader(c, page, pageSize, total))
+
 	response.Success(c, http.StatusOK, users, "Users retrieved successfully")
 }
 
+// buildUserListLinkHeader builds an RFC 5988 Link header with first/prev/
+// next/last page URLs for ListUsers, preserving the request's other query
+// parameters (email/role/status/sort filters).
+func buildUserListLinkHeader(c *gin.Context, page, pageSize, total int) string {
+	lastPage := 1
+	if pageSize > 0 && total > 0 {
+		lastPage = (total + pageSize - 1) / pageSize
+	}
+
+	pageURL := func(p int) string {
+		query := c.Request.URL.Query()
+		query.Set("page", strconv.Itoa(p))
+		query.Set("page_size", strconv.Itoa(pageSize))
+		u := *c.Request.URL
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(page-1)))
+	}
+	if page < lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(page+1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(lastPage)))
+
+	return strings.Join(links, ", ")
+}
+
 // CreateUser godoc
 // @Summary      Create a new user
 // @Description  Create a new user with email, password, and optional role (requires admin role). A verification email will be sent to the provided email address. User must verify their email before logging in.
@@ -238,6 +419,14 @@ func (h *Handler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	email, err := types.NewEmail(payload.Email)
+	if err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
 	// Hash the password
 	hashedPassword, err := HashPassword(payload.Password)
 	if err != nil {
@@ -275,7 +464,7 @@ func (h *Handler) CreateUser(c *gin.Context) {
 
 	// Create user with pending status (requires email verification)
 	user := User{
-		Email:                  payload.Email,
+		Email:                  email,
 		Password:               hashedPassword,
 		Role:                   role,
 		Status:                 StatusPending, // Set to pending until email is verified
@@ -296,7 +485,8 @@ func (h *Handler) CreateUser(c *gin.Context) {
 
 	// Send verification email if email service is configured
 	if h.emailService != nil && h.baseURL != "" {
-		if err := h.emailService.SendVerificationEmail(created.Email, verificationToken, h.baseURL); err != nil {
+		data := map[string]interface{}{"BaseURL": h.baseURL, "Token": verificationToken}
+		if err := h.emailService.SendTemplate(c.Request.Context(), "verification", created.Email, data); err != nil {
 			// Log error but don't fail user creation - email can be resent later
 			log.Printf("Failed to send verification email: %v", err)
 		}
@@ -311,15 +501,16 @@ func (h *Handler) CreateUser(c *gin.Context) {
 
 // DeleteUser godoc
 // @Summary      Delete a user by ID
-// @Description  Delete a user using its ID (requires admin role)
+// @Description  Delete a user using its ID (requires admin role). If the acting admin has 2FA enabled, a valid code must be supplied via the X-MFA-Code header.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id  path      string  true  "User ID"
+// @Param        X-MFA-Code  header    string  false  "Fresh TOTP code, required when the acting admin has 2FA enabled"
 // @Success      200  {object}  response.Response  "User deleted successfully"
 // @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
-// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden, or missing/invalid 2FA code"
 // @Failure      404  {object}  response.ErrorResponse  "User not found"
 // @Router       /users/{id} [delete]
 func (h *Handler) DeleteUser(c *gin.Context) {
@@ -329,6 +520,20 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	authUserID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	actingUser, exists := h.repo.Get(authUserID.(string))
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	if !h.requireFreshMFA(c, actingUser) {
+		return
+	}
+
 	if ok := h.repo.Delete(id); !ok {
 		response.NotFound(c, "User")
 		return
@@ -449,6 +654,108 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 	response.Success(c, http.StatusOK, updated, "User updated successfully")
 }
 
+// GetUserRole godoc
+// @Summary      Get a user's role
+// @Description  Get a single user's role by ID (requires the role.PermManageUsers permission)
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "User ID"
+// @Success      200  {object}  response.Response  "User role retrieved successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  response.ErrorResponse  "Forbidden"
+// @Failure      404  {object}  response.ErrorResponse  "User not found"
+// @Router       /users/{id}/role [get]
+func (h *Handler) GetUserRole(c *gin.Context) {
+	id := c.Param("id")
+	target, exists := h.repo.Get(id)
+	if !exists {
+		response.NotFound(c, "User")
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"role": target.Role}, "User role retrieved successfully")
+}
+
+// UpdateUserRole godoc
+// @Summary      Change a user's role
+// @Description  Change a single user's role by ID (requires the role.PermManageUsers permission). The acting user must outrank the target's current role and cannot grant a role higher than their own, so a plain Admin can change a User's role (to User or Admin) but can never promote anyone (including themselves) to Super Admin.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string                  true  "User ID"
+// @Param        role  body      updateUserRolePayload  true  "New role"
+// @Success      200   {object}  user.UserResponseWrapper  "User role updated successfully"
+// @Failure      400   {object}  response.ErrorResponse  "Validation error"
+// @Failure      401   {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      403   {object}  response.ErrorResponse  "Forbidden - acting user does not outrank the target, or the role being granted is above their own"
+// @Failure      404   {object}  response.ErrorResponse  "User not found"
+// @Failure      500   {object}  response.ErrorResponse  "Internal server error"
+// @Router       /users/{id}/role [put]
+func (h *Handler) UpdateUserRole(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.BadRequest(c, "User ID is required", nil)
+		return
+	}
+
+	var payload updateUserRolePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	validRole := false
+	for _, valid := range ValidRoles {
+		if payload.Role == valid {
+			validRole = true
+			break
+		}
+	}
+	if !validRole {
+		response.ValidationError(c, "Invalid role", map[string]interface{}{
+			"valid_roles": ValidRoles,
+		})
+		return
+	}
+
+	authUserRole, exists := c.Get("user_role")
+	if !exists {
+		response.Unauthorized(c, "User role not found")
+		return
+	}
+	actingRole := role.Role(authUserRole.(string))
+
+	target, exists := h.repo.Get(id)
+	if !exists {
+		response.NotFound(c, "User")
+		return
+	}
+
+	if !role.Outranks(actingRole, role.Role(target.Role)) {
+		response.Forbidden(c, "You do not have sufficient privilege to change this user's role")
+		return
+	}
+
+	newRole := role.Role(payload.Role)
+	if newRole != actingRole && !role.Outranks(actingRole, newRole) {
+		response.Forbidden(c, "You cannot grant a role higher than your own")
+		return
+	}
+
+	target.Role = payload.Role
+	updated, err := h.repo.Update(c.Request.Context(), id, target)
+	if err != nil {
+		response.InternalError(c, "Failed to update user role: "+err.Error())
+		return
+	}
+
+	updated.Password = ""
+	response.Success(c, http.StatusOK, updated, "User role updated successfully")
+}
+
 // ChangePassword godoc
 // @Summary      Change own password
 // @Description  Change the authenticated user's own password. Requires old password and new password. Users can only change their own password.
@@ -529,17 +836,18 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 
 // ChangeUserPassword godoc
 // @Summary      Change user password (Super Admin only)
-// @Description  Change a user's password by ID. Super Admin can change password for any user. Other roles can only change their own password (by passing their own user ID).
+// @Description  Change a user's password by ID. Super Admin can change password for any user. Other roles can only change their own password (by passing their own user ID). If the acting user has 2FA enabled, a valid code must be supplied via the X-MFA-Code header.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id    path      string                      true  "User ID"
 // @Param        credentials  body      adminChangePasswordPayload  true  "Password change credentials"
+// @Param        X-MFA-Code  header    string  false  "Fresh TOTP code, required when the acting user has 2FA enabled"
 // @Success      200         {object}  response.Response  "Password changed successfully"
 // @Failure      400         {object}  response.ErrorResponse  "Validation error"
 // @Failure      401         {object}  response.ErrorResponse  "Unauthorized"
-// @Failure      403         {object}  response.ErrorResponse  "Forbidden - can only change own password unless Super Admin"
+// @Failure      403         {object}  response.ErrorResponse  "Forbidden - can only change own password unless Super Admin, or missing/invalid 2FA code"
 // @Failure      404         {object}  response.ErrorResponse  "User not found"
 // @Failure      500         {object}  response.ErrorResponse  "Internal server error"
 // @Router       /users/{id}/change-password [post]
@@ -572,6 +880,15 @@ func (h *Handler) ChangeUserPassword(c *gin.Context) {
 		return
 	}
 
+	actingUser, exists := h.repo.Get(authUserIDStr)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	if !h.requireFreshMFA(c, actingUser) {
+		return
+	}
+
 	var payload adminChangePasswordPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		response.ValidationError(c, "Invalid request data", map[string]interface{}{
@@ -634,12 +951,109 @@ func (h *Handler) ChangeUserPassword(c *gin.Context) {
 	response.Success(c, http.StatusOK, updated, "Password changed successfully")
 }
 
+// ForgotPasswordHandler godoc
+// @Summary      Request a password reset email
+// @Description  Sends a time-limited password reset link to the given email if an account exists. Always returns 200 to avoid leaking which emails are registered. Rate limited to 3 requests per 15 minutes per IP+email pair.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      forgotPasswordPayload  true  "Email to send the reset link to"
+// @Success      200  {object}  response.Response  "Reset email sent if the account exists"
+// @Failure      400  {object}  response.ErrorResponse  "Validation error"
+// @Failure      429  {object}  response.ErrorResponse  "Too many reset requests"
+// @Router       /auth/forgot-password [post]
+func (h *Handler) ForgotPasswordHandler(c *gin.Context) {
+	var payload forgotPasswordPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	rateLimitKey := c.ClientIP() + "|" + payload.Email
+	if allowed, _, _ := h.passwordResetLimiter.Allow(rateLimitKey, passwordResetRateLimitCount, passwordResetRateLimitWindow); !allowed {
+		response.RateLimited(c)
+		return
+	}
+
+	// Always return the same success response whether or not the email
+	// exists (or is even well-formed), so callers can't use this endpoint
+	// to enumerate accounts.
+	if email, err := types.NewEmail(payload.Email); err == nil {
+		updated, resetToken, exists, err := h.repo.CreatePasswordResetToken(c.Request.Context(), email, passwordResetTokenTTL)
+		if err != nil {
+			log.Printf("Failed to create password reset token: %v", err)
+		} else if exists && h.emailService != nil && h.baseURL != "" {
+			data := map[string]interface{}{"BaseURL": h.baseURL, "Token": resetToken}
+			if err := h.emailService.SendTemplate(c.Request.Context(), "password_reset", updated.Email, data); err != nil {
+				log.Printf("Failed to send password reset email: %v", err)
+			}
+		}
+	}
+
+	response.SuccessNoContent(c, "If an account with that email exists, a password reset link has been sent.")
+}
+
+// ResetPasswordHandler godoc
+// @Summary      Reset password using a token
+// @Description  Validates the reset token from ForgotPasswordHandler, checks that it hasn't expired, and sets the new password
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      resetPasswordPayload  true  "Reset token and new password"
+// @Success      200  {object}  response.Response  "Password reset successfully"
+// @Failure      400  {object}  response.ErrorResponse  "Invalid, expired, or missing token"
+// @Failure      500  {object}  response.ErrorResponse  "Internal server error"
+// @Router       /auth/reset-password [post]
+func (h *Handler) ResetPasswordHandler(c *gin.Context) {
+	var payload resetPasswordPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	target, ok, err := h.repo.ConsumePasswordResetToken(c.Request.Context(), payload.Token)
+	if err != nil {
+		response.InternalError(c, "Failed to reset password: "+err.Error())
+		return
+	}
+	if !ok {
+		response.BadRequest(c, "Invalid or expired password reset token", nil)
+		return
+	}
+
+	hashedPassword, err := HashPassword(payload.NewPassword)
+	if err != nil {
+		response.InternalError(c, "Failed to hash password")
+		return
+	}
+
+	if _, err := h.repo.UpdatePassword(c.Request.Context(), target, hashedPassword); err != nil {
+		response.InternalError(c, "Failed to reset password: "+err.Error())
+		return
+	}
+
+	response.SuccessNoContent(c, "Password reset successfully")
+}
+
 // RegisterRequest represents the registration request payload
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
 }
 
+type forgotPasswordPayload struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type resetPasswordPayload struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
 type createUserPayload struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
@@ -652,6 +1066,10 @@ type updateUserPayload struct {
 	Status   string `json:"status"`   // Optional, must be: pending, active, or disabled
 }
 
+type updateUserRolePayload struct {
+	Role string `json:"role" binding:"required"`
+}
+
 type changePasswordPayload struct {
 	OldPassword string `json:"old_password" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required,min=6"`
@@ -1,12 +1,16 @@
 package user
 
-import "time"
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
 
 // ToAirtableFieldsForCreate converts a User to Airtable fields format for creation
 func (u *User) ToAirtableFieldsForCreate() map[string]interface{} {
 	now := time.Now().Format(time.RFC3339)
 	fields := map[string]interface{}{
-		FieldEmail:     u.Email,
+		FieldEmail:     string(u.Email),
 		FieldPassword:  u.Password, // Already hashed
 		FieldCreatedAt: now,
 		FieldUpdatedAt: now,
@@ -14,6 +18,15 @@ func (u *User) ToAirtableFieldsForCreate() map[string]interface{} {
 	if u.Role != "" {
 		fields[FieldRole] = u.Role
 	}
+	if u.Status != "" {
+		fields[FieldStatus] = u.Status
+	}
+	if u.EmailVerificationToken != "" {
+		fields[FieldEmailVerificationToken] = u.EmailVerificationToken
+	}
+	if len(u.OAuthIdentities) > 0 {
+		fields[FieldOAuthIdentities] = encodeOAuthIdentities(u.OAuthIdentities)
+	}
 	return fields
 }
 
@@ -21,7 +34,7 @@ func (u *User) ToAirtableFieldsForCreate() map[string]interface{} {
 func (u *User) ToAirtableFieldsForUpdate() map[string]interface{} {
 	now := time.Now().Format(time.RFC3339)
 	fields := map[string]interface{}{
-		FieldEmail:     u.Email,
+		FieldEmail:     string(u.Email),
 		FieldUpdatedAt: now,
 	}
 	if u.Password != "" {
@@ -30,6 +43,45 @@ func (u *User) ToAirtableFieldsForUpdate() map[string]interface{} {
 	if u.Role != "" {
 		fields[FieldRole] = u.Role
 	}
+	if u.Status != "" {
+		fields[FieldStatus] = u.Status
+	}
+	// EmailVerificationToken is always written on update (even when empty)
+	// so that VerifyEmailHandler can clear it once the address is confirmed.
+	fields[FieldEmailVerificationToken] = u.EmailVerificationToken
+	// PasswordResetToken/Expiry are always written on update (even when
+	// empty) so ResetPasswordHandler can invalidate a used token.
+	fields[FieldPasswordResetToken] = u.PasswordResetToken
+	fields[FieldPasswordResetExpiry] = u.PasswordResetExpiry
+	// TOTPSecret/Enabled/RecoveryCodes are always written on update (even
+	// when empty/false) so disabling 2FA or consuming a recovery code
+	// actually clears the stored value rather than leaving it stale.
+	fields[FieldTOTPSecret] = u.TOTPSecret
+	fields[FieldTOTPEnabled] = strconv.FormatBool(u.TOTPEnabled)
+	fields[FieldTOTPRecoveryCodes] = encodeRecoveryCodes(u.TOTPRecoveryCodes)
+	if len(u.OAuthIdentities) > 0 {
+		fields[FieldOAuthIdentities] = encodeOAuthIdentities(u.OAuthIdentities)
+	}
 	return fields
 }
 
+// encodeOAuthIdentities marshals OAuthIdentities to JSON for storage in
+// Airtable's single OAuthIdentities text field. Marshal errors are not
+// expected for this type and are swallowed to an empty array.
+func encodeOAuthIdentities(identities []OAuthIdentity) string {
+	raw, err := json.Marshal(identities)
+	if err != nil {
+		return "[]"
+	}
+	return string(raw)
+}
+
+// encodeRecoveryCodes marshals hashed TOTP recovery codes to JSON, the same
+// way encodeOAuthIdentities does for its list field.
+func encodeRecoveryCodes(codes []string) string {
+	raw, err := json.Marshal(codes)
+	if err != nil {
+		return "[]"
+	}
+	return string(raw)
+}
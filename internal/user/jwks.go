@@ -0,0 +1,20 @@
+package user
+
+// JWKSVerifier validates an RS256 JWT against a JWKS-published key set,
+// returning its claims in the same shape as ValidateToken. Implemented by
+// internal/authserver.KeySet.
+type JWKSVerifier interface {
+	Verify(tokenString string) (*Claims, error)
+}
+
+// jwksVerifier, when set, lets AuthMiddleware accept RS256 tokens issued by
+// the pluggable authorization server alongside the locally-signed HS256
+// tokens it has always accepted.
+var jwksVerifier JWKSVerifier
+
+// RegisterJWKSVerifier wires in verification of tokens issued by
+// internal/authserver. Without a registered verifier, AuthMiddleware only
+// accepts HS256 tokens signed with jwtSecret, same as before this existed.
+func RegisterJWKSVerifier(v JWKSVerifier) {
+	jwksVerifier = v
+}
@@ -1,22 +1,38 @@
 package user
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"lam-phuong-api/internal/role"
+	"lam-phuong-api/internal/types"
 )
 
 // Airtable field names
 const (
-	FieldEmail     = "Email"
-	FieldPassword  = "Password"
-	FieldRole      = "Role"
-	FieldCreatedAt = "CreatedAt"
-	FieldUpdatedAt = "UpdatedAt"
+	FieldEmail                  = "Email"
+	FieldPassword               = "Password"
+	FieldRole                   = "Role"
+	FieldStatus                 = "Status"
+	FieldEmailVerificationToken = "EmailVerificationToken"
+	FieldOAuthIdentities        = "OAuthIdentities"
+	FieldPasswordResetToken     = "PasswordResetToken"
+	FieldPasswordResetExpiry    = "PasswordResetExpiry"
+	FieldTOTPSecret             = "TOTPSecret"
+	FieldTOTPEnabled            = "TOTPEnabled"
+	FieldTOTPRecoveryCodes      = "TOTPRecoveryCodes"
+	FieldCreatedAt              = "CreatedAt"
+	FieldUpdatedAt              = "UpdatedAt"
 )
 
+// SortableFields lists the User fields Repository.Search accepts in its
+// sort parameter (optionally prefixed with "-" for descending).
+var SortableFields = []string{"email", "created_at"}
+
 // User roles
 const (
 	RoleSuperAdmin = "Super Admin"
@@ -24,6 +40,16 @@ const (
 	RoleUser       = "User"
 )
 
+// User account statuses
+const (
+	StatusPending  = "pending"
+	StatusActive   = "active"
+	StatusDisabled = "disabled"
+)
+
+// ValidStatuses contains all valid user account statuses
+var ValidStatuses = []string{StatusPending, StatusActive, StatusDisabled}
+
 // ValidRoles contains all valid user roles
 var ValidRoles = []string{RoleSuperAdmin, RoleAdmin, RoleUser}
 
@@ -37,12 +63,27 @@ func getStringField(fields map[string]interface{}, key string) string {
 	return ""
 }
 
+// OAuthIdentity links a User to an identity at an external SSO provider.
+type OAuthIdentity struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"` // The provider's stable user ID ("sub" claim for OIDC)
+}
+
 // User represents a user in the system
 type User struct {
-	ID       string `json:"id"`
-	Email    string `json:"email"`
-	Password string `json:"-"` // Never serialize password in JSON responses
-	Role     string `json:"role"`
+	ID                     string          `json:"id"`
+	Email                  types.Email     `json:"email"`
+	Password               string          `json:"-"` // Never serialize password in JSON responses
+	Role                   string          `json:"role"`
+	Status                 string          `json:"status"`
+	EmailVerificationToken string          `json:"-"`
+	OAuthIdentities        []OAuthIdentity `json:"oauth_identities,omitempty"`
+	PasswordResetToken     string          `json:"-"`
+	PasswordResetExpiry    string          `json:"-"` // RFC3339; empty means no reset in progress
+	TOTPSecret             string          `json:"-"`
+	TOTPEnabled            bool            `json:"totp_enabled"`
+	TOTPRecoveryCodes      []string        `json:"-"` // hashed; never serialized or re-shown after enrollment
+	CreatedAt              string          `json:"created_at,omitempty"`
 }
 
 // ToAirtableFields converts a User to Airtable fields format (for creation)
@@ -72,67 +113,135 @@ func FromAirtable(record map[string]interface{}) (*User, error) {
 		role = RoleUser // Default role
 	}
 
+	status := getStringField(fields, FieldStatus)
+	if status == "" {
+		status = StatusActive // Default for records created before Status existed
+	}
+
 	return &User{
-		ID:       id,
-		Email:    getStringField(fields, FieldEmail),
-		Password: getStringField(fields, FieldPassword),
-		Role:     role,
+		ID:                     id,
+		Email:                  types.Email(getStringField(fields, FieldEmail)),
+		Password:               getStringField(fields, FieldPassword),
+		Role:                   role,
+		Status:                 status,
+		EmailVerificationToken: getStringField(fields, FieldEmailVerificationToken),
+		OAuthIdentities:        decodeOAuthIdentities(getStringField(fields, FieldOAuthIdentities)),
+		PasswordResetToken:     getStringField(fields, FieldPasswordResetToken),
+		PasswordResetExpiry:    getStringField(fields, FieldPasswordResetExpiry),
+		TOTPSecret:             getStringField(fields, FieldTOTPSecret),
+		TOTPEnabled:            getStringField(fields, FieldTOTPEnabled) == "true",
+		TOTPRecoveryCodes:      decodeRecoveryCodes(getStringField(fields, FieldTOTPRecoveryCodes)),
+		CreatedAt:              getStringField(fields, FieldCreatedAt),
 	}, nil
 }
 
-// HashPassword hashes a plain text password using bcrypt
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", fmt.Errorf("failed to hash password: %w", err)
+// decodeOAuthIdentities unmarshals the JSON-encoded OAuthIdentities field
+// Airtable stores as a plain string (Airtable has no native nested-list
+// field type). Malformed or empty input yields nil rather than an error,
+// since this is a best-effort read path used by FromAirtable.
+func decodeOAuthIdentities(raw string) []OAuthIdentity {
+	if raw == "" {
+		return nil
 	}
-	return string(bytes), nil
+	var identities []OAuthIdentity
+	if err := json.Unmarshal([]byte(raw), &identities); err != nil {
+		return nil
+	}
+	return identities
 }
 
-// CheckPassword compares a plain text password with a hashed password
-func CheckPassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
+// decodeRecoveryCodes unmarshals the JSON-encoded TOTPRecoveryCodes field,
+// stored the same way as OAuthIdentities for the same reason (Airtable has
+// no native list field type). Malformed or empty input yields nil.
+func decodeRecoveryCodes(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var codes []string
+	if err := json.Unmarshal([]byte(raw), &codes); err != nil {
+		return nil
+	}
+	return codes
 }
 
 // Claims represents JWT claims
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID string      `json:"user_id"`
+	Email  types.Email `json:"email"`
+	Role   string      `json:"role"`
 	jwt.RegisteredClaims
 }
 
+// HasPermission reports whether c's role grants every bit set in p. An
+// unrecognized role grants nothing.
+func (c Claims) HasPermission(p role.Permissions) bool {
+	return role.Role(c.Role).Has(p)
+}
+
 // TokenResponse represents the response after successful authentication
 type TokenResponse struct {
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
 	ExpiresIn   int64  `json:"expires_in"`
 	User        User   `json:"user"`
+	// RefreshToken is only set when the refresh-token subsystem is
+	// configured (see Handler.SetRefreshTokenRepository).
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// RefreshExpiresIn is only set alongside RefreshToken, giving its
+	// lifetime in seconds the same way ExpiresIn does for AccessToken.
+	RefreshExpiresIn int64 `json:"refresh_expires_in,omitempty"`
 }
 
-// GenerateToken generates a JWT token for the user
-func GenerateToken(user User, secretKey string, expiresIn time.Duration) (string, error) {
+// GenerateToken generates a JWT token for the user, returning the token and
+// the random jti (RegisteredClaims.ID) it was issued with. Callers that also
+// issue a refresh token record the jti alongside it (see
+// refreshtoken.Token.AccessJTI) so the access token can be revoked by jti
+// later - on logout, rotation, or admin-initiated logout - without waiting
+// out the rest of its lifetime.
+func GenerateToken(user User, secretKey string, expiresIn time.Duration) (string, string, error) {
 	expirationTime := time.Now().Add(expiresIn)
 	role := user.Role
 	if role == "" {
 		role = RoleUser // Default role
 	}
+
+	jti, err := newJTI()
+	if err != nil {
+		return "", "", err
+	}
+
 	claims := &Claims{
 		UserID: user.ID,
 		Email:  user.Email,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secretKey))
+	signed, err := token.SignedString([]byte(secretKey))
+	if err != nil {
+		return "", "", err
+	}
+	return signed, jti, nil
+}
+
+// newJTI generates a random access-token identifier.
+func newJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// ValidateToken validates a JWT token and returns the claims. If a Revoker
+// has been registered (see RegisterRevoker) and the token's jti has been
+// revoked, validation fails even though the signature and expiry are still
+// good.
 func ValidateToken(tokenString, secretKey string) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -147,5 +256,9 @@ func ValidateToken(tokenString, secretKey string) (*Claims, error) {
 		return nil, jwt.ErrSignatureInvalid
 	}
 
+	if revoker != nil && claims.ID != "" && revoker.IsRevoked(claims.ID) {
+		return nil, fmt.Errorf("token has been revoked")
+	}
+
 	return claims, nil
 }
@@ -0,0 +1,349 @@
+package user
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/types"
+)
+
+// stateTTL bounds how long an OAuth login flow has to complete before its
+// state nonce is rejected as expired.
+const stateTTL = 10 * time.Minute
+
+// OAuthProviderConfig describes one OAuth2/OIDC SSO provider: Google,
+// GitHub, or a generic OIDC issuer.
+type OAuthProviderConfig struct {
+	Name         string
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	Scopes       []string
+	RedirectURL  string
+}
+
+// SetOAuthProviders configures the SSO backends reachable at
+// /auth/oauth/:provider/login and /auth/oauth/:provider/callback. Without a
+// call to this, those routes 404 (via provider lookup failing, not routing).
+func (h *Handler) SetOAuthProviders(providers []OAuthProviderConfig) {
+	h.oauthProviders = make(map[string]OAuthProviderConfig, len(providers))
+	for _, p := range providers {
+		h.oauthProviders[p.Name] = p
+	}
+}
+
+// OAuthLogin godoc
+// @Summary      Start an OAuth2/OIDC SSO login
+// @Description  Redirects the browser to the named provider's authorization endpoint with a signed state nonce
+// @Tags         auth
+// @Param        provider  path  string  true  "Provider name, e.g. google"
+// @Success      302
+// @Failure      404  {object}  response.ErrorResponse  "Unknown provider"
+// @Router       /auth/oauth/{provider}/login [get]
+func (h *Handler) OAuthLogin(c *gin.Context) {
+	providerName := c.Param("provider")
+	provider, ok := h.oauthProviders[providerName]
+	if !ok {
+		response.NotFound(c, "OAuth provider")
+		return
+	}
+
+	state, err := h.signState(providerName)
+	if err != nil {
+		response.InternalError(c, "Failed to start OAuth login: "+err.Error())
+		return
+	}
+
+	query := url.Values{
+		"client_id":     {provider.ClientID},
+		"redirect_uri":  {provider.RedirectURL},
+		"response_type": {"code"},
+		"scope":         {strings.Join(provider.Scopes, " ")},
+		"state":         {state},
+	}
+
+	c.Redirect(http.StatusFound, provider.AuthURL+"?"+query.Encode())
+}
+
+// OAuthCallback godoc
+// @Summary      Complete an OAuth2/OIDC SSO login
+// @Description  Exchanges the authorization code for tokens, fetches userinfo, links or creates the local User, and issues a JWT
+// @Tags         auth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name, e.g. google"
+// @Param        code      query     string  true  "Authorization code"
+// @Param        state     query     string  true  "State nonce issued by OAuthLogin"
+// @Success      200  {object}  user.TokenResponseWrapper  "Login successful"
+// @Failure      400  {object}  response.ErrorResponse  "Invalid or expired state"
+// @Failure      404  {object}  response.ErrorResponse  "Unknown provider"
+// @Failure      500  {object}  response.ErrorResponse  "Internal server error"
+// @Router       /auth/oauth/{provider}/callback [get]
+func (h *Handler) OAuthCallback(c *gin.Context) {
+	providerName := c.Param("provider")
+	provider, ok := h.oauthProviders[providerName]
+	if !ok {
+		response.NotFound(c, "OAuth provider")
+		return
+	}
+
+	state := c.Query("state")
+	if err := h.verifyState(providerName, state); err != nil {
+		response.BadRequest(c, "Invalid or expired OAuth state: "+err.Error(), nil)
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		response.BadRequest(c, "Missing authorization code", nil)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	accessToken, err := exchangeCodeForToken(ctx, provider, code)
+	if err != nil {
+		response.InternalError(c, "Failed to exchange OAuth code: "+err.Error())
+		return
+	}
+
+	info, err := fetchUserInfo(ctx, provider, accessToken)
+	if err != nil {
+		response.InternalError(c, "Failed to fetch OAuth userinfo: "+err.Error())
+		return
+	}
+
+	if info.Email == "" {
+		response.InternalError(c, "OAuth provider did not return an email address")
+		return
+	}
+
+	email, err := types.NewEmail(info.Email)
+	if err != nil {
+		response.InternalError(c, "OAuth provider returned an invalid email address")
+		return
+	}
+
+	authedUser, err := h.linkOrCreateOAuthUser(ctx, providerName, email, info)
+	if err != nil {
+		response.InternalError(c, "Failed to link OAuth account: "+err.Error())
+		return
+	}
+
+	token, jti, err := GenerateToken(authedUser, h.jwtSecret, h.tokenExpiry)
+	if err != nil {
+		response.InternalError(c, "Failed to generate token")
+		return
+	}
+
+	refreshToken, err := h.issueRefreshToken(ctx, c, authedUser.ID, jti, time.Now().Add(h.tokenExpiry))
+	if err != nil {
+		log.Printf("Failed to issue refresh token: %v", err)
+	}
+
+	authedUser.Password = ""
+	tokenResp := TokenResponse{
+		AccessToken:  token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(h.tokenExpiry.Seconds()),
+		User:         authedUser,
+		RefreshToken: refreshToken,
+	}
+	if refreshToken != "" {
+		tokenResp.RefreshExpiresIn = int64(refreshTokenTTL.Seconds())
+	}
+	response.Success(c, http.StatusOK, tokenResp, "Login successful")
+}
+
+// linkOrCreateOAuthUser finds the local User matching email (the IdP already
+// verified it, so we trust it outright) and attaches the OAuth identity, or
+// provisions a brand new User with a random, unusable password hash when none
+// exists yet.
+func (h *Handler) linkOrCreateOAuthUser(ctx context.Context, providerName string, email types.Email, info oauthUserInfo) (User, error) {
+	existing, found := h.repo.GetByEmail(email)
+
+	target := existing
+	if !found {
+		randomPassword, err := GenerateVerificationToken()
+		if err != nil {
+			return User{}, fmt.Errorf("failed to generate random password: %w", err)
+		}
+		hashedPassword, err := HashPassword(randomPassword)
+		if err != nil {
+			return User{}, fmt.Errorf("failed to hash random password: %w", err)
+		}
+		target = User{
+			Email:    email,
+			Password: hashedPassword,
+			Role:     RoleUser,
+		}
+	}
+
+	target.Status = StatusActive // IdP already verified the email; skip our own verification step
+	target.EmailVerificationToken = ""
+	target.OAuthIdentities = upsertOAuthIdentity(target.OAuthIdentities, OAuthIdentity{
+		Provider: providerName,
+		Subject:  info.Subject,
+	})
+
+	return h.repo.Upsert(ctx, target)
+}
+
+func upsertOAuthIdentity(identities []OAuthIdentity, identity OAuthIdentity) []OAuthIdentity {
+	for i, existing := range identities {
+		if existing.Provider == identity.Provider {
+			identities[i] = identity
+			return identities
+		}
+	}
+	return append(identities, identity)
+}
+
+// oauthUserInfo is the subset of OIDC standard claims we need, shared by
+// Google, GitHub (which is close enough to OIDC for our purposes), and
+// generic OIDC providers.
+type oauthUserInfo struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email"`
+}
+
+func exchangeCodeForToken(ctx context.Context, provider OAuthProviderConfig, code string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {provider.RedirectURL},
+		"client_id":     {provider.ClientID},
+		"client_secret": {provider.ClientSecret},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("token endpoint response did not include an access_token")
+	}
+
+	return tokenResp.AccessToken, nil
+}
+
+func fetchUserInfo(ctx context.Context, provider OAuthProviderConfig, accessToken string) (oauthUserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.UserInfoURL, nil)
+	if err != nil {
+		return oauthUserInfo{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return oauthUserInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return oauthUserInfo{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return oauthUserInfo{}, fmt.Errorf("userinfo endpoint returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var info oauthUserInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return oauthUserInfo{}, fmt.Errorf("failed to parse userinfo response: %w", err)
+	}
+
+	return info, nil
+}
+
+// signState produces a self-contained state value (provider|expiry|nonce
+// signed with the server's JWT secret) so OAuthCallback can verify it
+// without needing server-side session storage.
+func (h *Handler) signState(providerName string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	payload := fmt.Sprintf("%s|%d|%s", providerName, time.Now().Add(stateTTL).Unix(), hex.EncodeToString(nonce))
+	sig := h.signPayload(payload)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload + "|" + sig)), nil
+}
+
+func (h *Handler) verifyState(providerName, state string) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(state)
+	if err != nil {
+		return fmt.Errorf("malformed state")
+	}
+
+	parts := strings.SplitN(string(decoded), "|", 4)
+	if len(parts) != 4 {
+		return fmt.Errorf("malformed state")
+	}
+
+	payload := strings.Join(parts[:3], "|")
+	wantedSig := h.signPayload(payload)
+	if !hmac.Equal([]byte(parts[3]), []byte(wantedSig)) {
+		return fmt.Errorf("state signature mismatch")
+	}
+
+	if parts[0] != providerName {
+		return fmt.Errorf("state issued for a different provider")
+	}
+
+	var expiresAt int64
+	if _, err := fmt.Sscanf(parts[1], "%d", &expiresAt); err != nil {
+		return fmt.Errorf("malformed state expiry")
+	}
+	if time.Now().Unix() > expiresAt {
+		return fmt.Errorf("state expired")
+	}
+
+	return nil
+}
+
+func (h *Handler) signPayload(payload string) string {
+	mac := hmac.New(sha256.New, []byte(h.jwtSecret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,142 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// argon2idPrefix marks a PHC-formatted argon2id hash, as opposed to a
+// legacy bcrypt hash (which starts with "$2a$"/"$2b$"/"$2y$").
+const argon2idPrefix = "$argon2id$"
+
+// Argon2Params controls the cost of new password hashes. Tune Memory
+// (KiB), Iterations, and Parallelism per deployment via config; see
+// config.AuthConfig.Argon2.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params match the OWASP-recommended minimums for argon2id.
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024, // 64 MiB
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// argon2Params is the package-wide setting used by HashPassword for new
+// hashes. Set once at startup via SetArgon2Params; existing stored hashes
+// embed their own parameters and keep verifying correctly even after this
+// changes.
+var argon2Params = DefaultArgon2Params
+
+// SetArgon2Params configures the cost parameters used for new password
+// hashes. Without a call to this, HashPassword uses DefaultArgon2Params.
+func SetArgon2Params(p Argon2Params) {
+	argon2Params = p
+}
+
+// HashPassword hashes a plain text password with argon2id, encoding the
+// result as a PHC string (`$argon2id$v=19$m=...,t=...,p=...$<salt>$<hash>`)
+// so CheckPassword can recover the exact parameters used even after
+// argon2Params changes.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argon2Params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	hash := argon2.IDKey([]byte(password), salt, argon2Params.Iterations, argon2Params.Memory, argon2Params.Parallelism, argon2Params.KeyLength)
+
+	encoded := fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		argon2Params.Memory, argon2Params.Iterations, argon2Params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	)
+	return encoded, nil
+}
+
+// CheckPassword compares a plain text password against a hash produced by
+// either HashPassword (argon2id) or the legacy bcrypt encoder, dispatching
+// on the hash's prefix.
+func CheckPassword(hashedPassword, password string) bool {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return checkArgon2idPassword(hashedPassword, password)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
+}
+
+// GenerateVerificationToken generates a secure random token, used for email
+// verification links and password reset links alike.
+func GenerateVerificationToken() (string, error) {
+	b := make([]byte, 32) // 64 character hex string
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// needsRehash reports whether hashedPassword is a legacy bcrypt hash that
+// should be transparently upgraded to argon2id the next time its plaintext
+// is available (i.e. right after a successful login).
+func needsRehash(hashedPassword string) bool {
+	return !strings.HasPrefix(hashedPassword, argon2idPrefix)
+}
+
+func checkArgon2idPassword(encoded, password string) bool {
+	params, salt, wantHash, err := decodeArgon2idHash(encoded)
+	if err != nil {
+		return false
+	}
+
+	gotHash := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(wantHash)))
+	return subtle.ConstantTimeCompare(gotHash, wantHash) == 1
+}
+
+// decodeArgon2idHash parses a PHC-formatted argon2id hash back into its
+// parameters, salt, and derived key.
+func decodeArgon2idHash(encoded string) (params Argon2Params, salt, hash []byte, err error) {
+	parts := strings.Split(encoded, "$")
+	// parts[0] is "" (leading $); parts[1] = "argon2id"; parts[2] = "v=19";
+	// parts[3] = "m=...,t=...,p=..."; parts[4] = salt; parts[5] = hash.
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id version segment: %w", err)
+	}
+	if version != argon2.Version {
+		return Argon2Params{}, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id parameter segment: %w", err)
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id salt encoding: %w", err)
+	}
+
+	hash, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id hash encoding: %w", err)
+	}
+
+	return params, salt, hash, nil
+}
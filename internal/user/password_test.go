@@ -0,0 +1,85 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// targetHashDuration is the approximate per-hash cost DefaultArgon2Params
+// should land on for a typical CI machine; OWASP's argon2id guidance caps
+// at "no more than ~1 second" and recommends hundreds of milliseconds, so
+// this asserts only a loose upper bound (4x the target) rather than an
+// exact value.
+const targetHashDuration = 250 * time.Millisecond
+
+func TestHashPasswordTiming(t *testing.T) {
+	argon2Params = DefaultArgon2Params
+
+	start := time.Now()
+	if _, err := HashPassword("a reasonably long test password"); err != nil {
+		t.Fatalf("HashPassword returned an error: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if elapsed > 4*targetHashDuration {
+		t.Errorf("HashPassword took %s, more than 4x the %s target; argon2 params may be too expensive for this deployment", elapsed, targetHashDuration)
+	}
+}
+
+func TestCheckPasswordArgon2idRoundTrip(t *testing.T) {
+	hash, err := HashPassword("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("HashPassword returned an error: %v", err)
+	}
+
+	if !CheckPassword(hash, "correct horse battery staple") {
+		t.Error("CheckPassword rejected the correct password")
+	}
+	if CheckPassword(hash, "wrong password") {
+		t.Error("CheckPassword accepted an incorrect password")
+	}
+}
+
+func TestCheckPasswordLegacyBcrypt(t *testing.T) {
+	// Simulates a row hashed before argon2id adoption; CheckPassword must
+	// still verify it via the bcrypt path.
+	legacyBytes, err := bcrypt.GenerateFromPassword([]byte("legacy-password"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("failed to generate legacy bcrypt hash: %v", err)
+	}
+	legacyHash := string(legacyBytes)
+
+	if !CheckPassword(legacyHash, "legacy-password") {
+		t.Error("CheckPassword rejected a valid legacy bcrypt hash")
+	}
+	if CheckPassword(legacyHash, "wrong-password") {
+		t.Error("CheckPassword accepted an incorrect password against a legacy bcrypt hash")
+	}
+	if !needsRehash(legacyHash) {
+		t.Error("needsRehash should flag a legacy bcrypt hash for migration")
+	}
+	if needsRehash(mustHash(t, "anything")) {
+		t.Error("needsRehash should not flag a freshly generated argon2id hash")
+	}
+}
+
+func mustHash(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := HashPassword(password)
+	if err != nil {
+		t.Fatalf("HashPassword returned an error: %v", err)
+	}
+	return hash
+}
+
+func BenchmarkHashPassword(b *testing.B) {
+	argon2Params = DefaultArgon2Params
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := HashPassword("benchmark password"); err != nil {
+			b.Fatalf("HashPassword returned an error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/refreshtoken"
+	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/revocation"
+)
+
+// refreshTokenTTL bounds how long an issued refresh token may be redeemed
+// before the user has to log in again with credentials.
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// SetRefreshTokenRepository enables the refresh-token subsystem: issuing a
+// refresh token alongside the access JWT on login, and the
+// /auth/refresh, /auth/logout, /auth/logout-all, /auth/sessions routes.
+// Without a call to this, Login issues access tokens only.
+func (h *Handler) SetRefreshTokenRepository(repo refreshtoken.Repository) {
+	h.refreshTokens = repo
+}
+
+// SetRevocationStore enables immediate access-token revocation: logout,
+// refresh rotation, and admin-initiated logout all blacklist the affected
+// access token's jti here instead of just letting it run out its remaining
+// lifetime. Register the same Handler with user.RegisterRevoker (the
+// Handler itself implements Revoker, see IsRevoked) so ValidateToken
+// consults it.
+func (h *Handler) SetRevocationStore(store revocation.Store) {
+	h.revocations = store
+}
+
+// IsRevoked implements Revoker by consulting the configured revocation
+// store. Reports false (not revoked) when none is configured.
+func (h *Handler) IsRevoked(jti string) bool {
+	if h.revocations == nil || jti == "" {
+		return false
+	}
+	return h.revocations.IsRevoked(jti)
+}
+
+// revokeAccessToken blacklists the access token identified by jti (validUntil
+// bounds how long it's worth remembering) when a revocation store is
+// configured. It's best-effort: a failure here doesn't undo the refresh-token
+// revocation that triggered it.
+func (h *Handler) revokeAccessToken(jti string, validUntil time.Time) {
+	if h.revocations == nil || jti == "" {
+		return
+	}
+	_ = h.revocations.Revoke(jti, validUntil)
+}
+
+// issueRefreshToken creates and persists a new refresh token for userID,
+// returning the raw value to hand back to the client. accessJTI and
+// accessExpiresAt identify the access token issued alongside it (see
+// GenerateToken), so revoking this refresh token can also revoke that
+// access token; pass "" / the zero Time when no access token was issued.
+// Returns "", nil when the subsystem isn't configured.
+func (h *Handler) issueRefreshToken(ctx context.Context, c *gin.Context, userID string, accessJTI string, accessExpiresAt time.Time) (string, error) {
+	if h.refreshTokens == nil {
+		return "", nil
+	}
+
+	raw, err := refreshtoken.GenerateRawToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = h.refreshTokens.Create(ctx, refreshtoken.Token{
+		UserHash:        refreshtoken.Hash(userID),
+		TokenHash:       refreshtoken.Hash(raw),
+		IssuedAt:        now,
+		ExpiresAt:       now.Add(refreshTokenTTL),
+		UserAgent:       c.Request.UserAgent(),
+		IP:              c.ClientIP(),
+		AccessJTI:       accessJTI,
+		AccessExpiresAt: accessExpiresAt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// userByHash finds the User whose ID hashes to userHash. Token rows only
+// ever carry the hash (so a leaked refresh-token table doesn't also hand
+// out a token->user-ID mapping), so recovering the User for a presented
+// refresh token means scanning the user list rather than a direct lookup.
+func (h *Handler) userByHash(userHash string) (User, bool) {
+	for _, u := range h.repo.List() {
+		if refreshtoken.Hash(u.ID) == userHash {
+			return u, true
+		}
+	}
+	return User{}, false
+}
+
+// RefreshTokenRequest is the payload for /auth/refresh and /auth/logout.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RefreshTokenHandler godoc
+// @Summary      Exchange a refresh token for a new access+refresh pair
+// @Description  Validates the presented refresh token, revokes it, and issues a new access JWT and refresh token (rotation). Re-presenting an already-rotated token revokes every session for that user (reuse detection).
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      RefreshTokenRequest  true  "Refresh token"
+// @Success      200  {object}  user.TokenResponseWrapper  "New token pair issued"
+// @Failure      400  {object}  response.ErrorResponse  "Validation error"
+// @Failure      401  {object}  response.ErrorResponse  "Invalid, expired, or reused refresh token"
+// @Failure      500  {object}  response.ErrorResponse  "Internal server error"
+// @Router       /auth/refresh [post]
+func (h *Handler) RefreshTokenHandler(c *gin.Context) {
+	if h.refreshTokens == nil {
+		response.InternalError(c, "Refresh tokens are not configured")
+		return
+	}
+
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tokenHash := refreshtoken.Hash(req.RefreshToken)
+
+	existing, found := h.refreshTokens.GetByTokenHash(ctx, tokenHash)
+	if !found {
+		response.InvalidToken(c, "Invalid refresh token")
+		return
+	}
+
+	if existing.RevokedWithoutReplacement() {
+		// The legitimate holder already received the replacement from the
+		// earlier rotation, so this presentation means the token leaked.
+		_ = h.refreshTokens.RevokeAllForUser(ctx, existing.UserHash)
+		response.InvalidToken(c, "Refresh token has already been used; all sessions have been revoked")
+		return
+	}
+
+	if !existing.Active() {
+		response.ExpiredToken(c)
+		return
+	}
+
+	targetUser, found := h.userByHash(existing.UserHash)
+	if !found || targetUser.Status != StatusActive {
+		response.InvalidToken(c, "Invalid refresh token")
+		return
+	}
+
+	accessToken, accessJTI, err := GenerateToken(targetUser, h.jwtSecret, h.tokenExpiry)
+	if err != nil {
+		response.InternalError(c, "Failed to generate token")
+		return
+	}
+	accessExpiresAt := time.Now().Add(h.tokenExpiry)
+
+	newRaw, err := refreshtoken.GenerateRawToken()
+	if err != nil {
+		response.InternalError(c, "Failed to generate refresh token")
+		return
+	}
+
+	now := time.Now()
+	replacement, err := h.refreshTokens.Create(ctx, refreshtoken.Token{
+		UserHash:        existing.UserHash,
+		TokenHash:       refreshtoken.Hash(newRaw),
+		IssuedAt:        now,
+		ExpiresAt:       now.Add(refreshTokenTTL),
+		UserAgent:       c.Request.UserAgent(),
+		IP:              c.ClientIP(),
+		AccessJTI:       accessJTI,
+		AccessExpiresAt: accessExpiresAt,
+	})
+	if err != nil {
+		response.InternalError(c, "Failed to rotate refresh token: "+err.Error())
+		return
+	}
+
+	if err := h.refreshTokens.Revoke(ctx, existing.ID, replacement.ID); err != nil {
+		response.InternalError(c, "Failed to revoke previous refresh token: "+err.Error())
+		return
+	}
+	// The access token paired with the refresh token we just rotated away
+	// from is no longer the caller's current one; blacklist it rather than
+	// leaving it valid until it naturally expires.
+	h.revokeAccessToken(existing.AccessJTI, existing.AccessExpiresAt)
+
+	targetUser.Password = ""
+	response.Success(c, http.StatusOK, TokenResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(h.tokenExpiry.Seconds()),
+		User:             targetUser,
+		RefreshToken:     newRaw,
+		RefreshExpiresIn: int64(refreshTokenTTL.Seconds()),
+	}, "Token refreshed successfully")
+}
+
+// LogoutHandler godoc
+// @Summary      Log out of the current session
+// @Description  Revokes the presented refresh token and, when a revocation store is configured, the access token issued alongside it.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      RefreshTokenRequest  true  "Refresh token to revoke"
+// @Success      200  {object}  response.Response  "Logged out successfully"
+// @Failure      400  {object}  response.ErrorResponse  "Validation error"
+// @Router       /auth/logout [post]
+func (h *Handler) LogoutHandler(c *gin.Context) {
+	if h.refreshTokens == nil {
+		response.SuccessNoContent(c, "Logged out successfully")
+		return
+	}
+
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if existing, found := h.refreshTokens.GetByTokenHash(ctx, refreshtoken.Hash(req.RefreshToken)); found {
+		_ = h.refreshTokens.Revoke(ctx, existing.ID, "")
+		h.revokeAccessToken(existing.AccessJTI, existing.AccessExpiresAt)
+	}
+
+	response.SuccessNoContent(c, "Logged out successfully")
+}
+
+// LogoutAllHandler godoc
+// @Summary      Log out of every session
+// @Description  Revokes every active refresh token belonging to the authenticated user
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "All sessions revoked"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Router       /auth/logout-all [post]
+func (h *Handler) LogoutAllHandler(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if h.refreshTokens != nil {
+		if err := h.revokeAllSessions(c.Request.Context(), userID.(string)); err != nil {
+			response.InternalError(c, "Failed to revoke sessions: "+err.Error())
+			return
+		}
+	}
+
+	response.SuccessNoContent(c, "All sessions revoked")
+}
+
+// revokeAllSessions revokes every active refresh token for the user with
+// the given ID, along with the access token issued alongside each one.
+func (h *Handler) revokeAllSessions(ctx context.Context, userID string) error {
+	userHash := refreshtoken.Hash(userID)
+
+	tokens, err := h.refreshTokens.ListByUserHash(ctx, userHash)
+	if err != nil {
+		return err
+	}
+
+	if err := h.refreshTokens.RevokeAllForUser(ctx, userHash); err != nil {
+		return err
+	}
+
+	for _, t := range tokens {
+		if t.RevokedAt.IsZero() {
+			h.revokeAccessToken(t.AccessJTI, t.AccessExpiresAt)
+		}
+	}
+	return nil
+}
+
+// RevokeUserSessionsHandler godoc
+// @Summary      Force-log-out a user (admin)
+// @Description  Revokes every refresh token belonging to the given user, and the access token issued alongside each one, so an admin can end a compromised or unwanted session without waiting for natural token expiry.
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "User ID"
+// @Success      200  {object}  response.Response  "All sessions revoked"
+// @Failure      404  {object}  response.ErrorResponse  "User not found"
+// @Failure      500  {object}  response.ErrorResponse  "Internal server error"
+// @Router       /users/{id}/revoke-sessions [post]
+func (h *Handler) RevokeUserSessionsHandler(c *gin.Context) {
+	id := c.Param("id")
+	if _, exists := h.repo.Get(id); !exists {
+		response.NotFound(c, "User")
+		return
+	}
+
+	if h.refreshTokens != nil {
+		if err := h.revokeAllSessions(c.Request.Context(), id); err != nil {
+			response.InternalError(c, "Failed to revoke sessions: "+err.Error())
+			return
+		}
+	}
+
+	response.SuccessNoContent(c, "All sessions revoked")
+}
+
+// SessionResponse describes one issued refresh token for GET /auth/sessions,
+// omitting the token hash itself.
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	Revoked   bool      `json:"revoked"`
+}
+
+// ListSessionsHandler godoc
+// @Summary      List active sessions
+// @Description  Returns the authenticated user's active (unrevoked, unexpired) refresh tokens (sessions). No route revokes a single session by its ID; POST /auth/logout revokes only the session whose refresh token is presented.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   user.SessionResponse  "Sessions retrieved successfully"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Router       /auth/sessions [get]
+func (h *Handler) ListSessionsHandler(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	sessions := make([]SessionResponse, 0)
+	if h.refreshTokens != nil {
+		tokens, err := h.refreshTokens.ListByUserHash(c.Request.Context(), refreshtoken.Hash(userID.(string)))
+		if err != nil {
+			response.InternalError(c, "Failed to list sessions: "+err.Error())
+			return
+		}
+		for _, t := range tokens {
+			if !t.Active() {
+				continue
+			}
+			sessions = append(sessions, SessionResponse{
+				ID:        t.ID,
+				IssuedAt:  t.IssuedAt,
+				ExpiresAt: t.ExpiresAt,
+				UserAgent: t.UserAgent,
+				IP:        t.IP,
+				Revoked:   !t.RevokedAt.IsZero(),
+			})
+		}
+	}
+
+	response.Success(c, http.StatusOK, sessions, "Sessions retrieved successfully")
+}
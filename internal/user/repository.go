@@ -2,22 +2,124 @@ package user
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"lam-phuong-api/internal/airtable"
+	"lam-phuong-api/internal/replication"
+	"lam-phuong-api/internal/types"
 )
 
 // Repository defines behavior for storing and retrieving users
 type Repository interface {
 	List() []User
 	Create(ctx context.Context, user User) (User, error)
+	// Update overwrites the mutable fields of the user with the given id.
+	Update(ctx context.Context, id string, user User) (User, error)
 	Delete(id string) bool
-	GetByEmail(email string) (User, bool)
+	// Get retrieves a user by ID.
+	Get(id string) (User, bool)
+	GetByEmail(email types.Email) (User, bool)
+	// GetByResetToken retrieves a user by password reset token, using a
+	// timing-safe comparison so a failed guess doesn't leak (via response
+	// timing) whether it almost matched a real token.
+	GetByResetToken(token string) (User, bool)
+	// GetByVerificationToken retrieves a user by email verification token.
+	GetByVerificationToken(token string) (User, bool)
+	// Upsert creates the user if user.ID is empty, or updates the existing
+	// record with that ID otherwise. It is used by Authenticators (e.g.
+	// LDAP, JWT) to provision or refresh users on first login.
+	Upsert(ctx context.Context, user User) (User, error)
+	// Search returns a filtered, sorted, paginated page of users plus the
+	// total number of users matching filter (before pagination), for
+	// Handler.ListUsers.
+	Search(ctx context.Context, filter UserFilter) ([]User, int, error)
+	// CreatePasswordResetToken generates and stores a password reset token,
+	// valid until ttl from now, for the user with the given email. It
+	// reports exists=false (not an error) if no user has that email, so
+	// ForgotPasswordHandler can return its anti-enumeration response
+	// either way.
+	CreatePasswordResetToken(ctx context.Context, email types.Email, ttl time.Duration) (user User, token string, exists bool, err error)
+	// ConsumePasswordResetToken looks up the user holding token, checks it
+	// hasn't expired, and clears it so it cannot be reused. ok=false means
+	// the token is missing, unknown, or expired.
+	ConsumePasswordResetToken(ctx context.Context, token string) (user User, ok bool, err error)
+	// UpdatePassword overwrites user's password with hashedPassword
+	// (expected to already be hashed via HashPassword) and persists it.
+	UpdatePassword(ctx context.Context, user User, hashedPassword string) (User, error)
+}
+
+// UserFilter selects, sorts, and paginates a subset of users for
+// Repository.Search.
+type UserFilter struct {
+	Email  string // substring match, case-insensitive
+	Role   string // exact match
+	Status string // exact match
+	// Sort is one of SortableFields, optionally prefixed with "-" for
+	// descending. Defaults to "email" ascending when empty.
+	Sort   string
+	Offset int
+	Limit  int // <= 0 means unlimited
+}
+
+// filterSortPaginate applies a UserFilter to an in-memory slice of users.
+// Shared by InMemoryRepository.Search and AirtableRepository.Search (which
+// fetches the full list from Airtable first), since Airtable has no native
+// substring-filter/sort/paginate support worth round-tripping for.
+func filterSortPaginate(users []User, filter UserFilter) ([]User, int) {
+	emailQuery := strings.ToLower(filter.Email)
+
+	filtered := make([]User, 0, len(users))
+	for _, u := range users {
+		if emailQuery != "" && !strings.Contains(strings.ToLower(string(u.Email)), emailQuery) {
+			continue
+		}
+		if filter.Role != "" && u.Role != filter.Role {
+			continue
+		}
+		if filter.Status != "" && u.Status != filter.Status {
+			continue
+		}
+		filtered = append(filtered, u)
+	}
+
+	sortKey := strings.TrimPrefix(filter.Sort, "-")
+	descending := strings.HasPrefix(filter.Sort, "-")
+	if sortKey == "" {
+		sortKey = "email"
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		var cmp int
+		switch sortKey {
+		case "created_at":
+			cmp = strings.Compare(filtered[i].CreatedAt, filtered[j].CreatedAt)
+		default:
+			cmp = strings.Compare(string(filtered[i].Email), string(filtered[j].Email))
+		}
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+
+	total := len(filtered)
+
+	start := filter.Offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if filter.Limit > 0 && start+filter.Limit < total {
+		end = start + filter.Limit
+	}
+
+	return filtered[start:end], total
 }
 
 // InMemoryRepository stores users in memory and is safe for concurrent access
@@ -75,11 +177,28 @@ func (r *InMemoryRepository) Create(ctx context.Context, user User) (User, error
 
 	user.ID = strconv.Itoa(r.nextID)
 	r.nextID++
+	if user.CreatedAt == "" {
+		user.CreatedAt = time.Now().Format(time.RFC3339)
+	}
 	r.data[user.ID] = user
 
 	return user, nil
 }
 
+// Search filters, sorts, and paginates the in-memory user set.
+func (r *InMemoryRepository) Search(ctx context.Context, filter UserFilter) ([]User, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]User, 0, len(r.data))
+	for _, user := range r.data {
+		users = append(users, user)
+	}
+
+	result, total := filterSortPaginate(users, filter)
+	return result, total, nil
+}
+
 // Delete removes a user by ID
 func (r *InMemoryRepository) Delete(id string) bool {
 	r.mu.Lock()
@@ -93,8 +212,31 @@ func (r *InMemoryRepository) Delete(id string) bool {
 	return true
 }
 
+// Get retrieves a user by ID
+func (r *InMemoryRepository) Get(id string) (User, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, exists := r.data[id]
+	return user, exists
+}
+
+// GetByVerificationToken retrieves a user by email verification token
+func (r *InMemoryRepository) GetByVerificationToken(token string) (User, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.data {
+		if user.EmailVerificationToken != "" && user.EmailVerificationToken == token {
+			return user, true
+		}
+	}
+
+	return User{}, false
+}
+
 // GetByEmail retrieves a user by email
-func (r *InMemoryRepository) GetByEmail(email string) (User, bool) {
+func (r *InMemoryRepository) GetByEmail(email types.Email) (User, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -107,11 +249,117 @@ func (r *InMemoryRepository) GetByEmail(email string) (User, bool) {
 	return User{}, false
 }
 
+// GetByResetToken retrieves a user by password reset token. It scans every
+// record and compares with subtle.ConstantTimeCompare without an early
+// return, so the time this takes doesn't depend on whether (or where) a
+// match is found.
+func (r *InMemoryRepository) GetByResetToken(token string) (User, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokenBytes := []byte(token)
+	var match User
+	found := false
+	for _, user := range r.data {
+		if user.PasswordResetToken == "" {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(user.PasswordResetToken), tokenBytes) == 1 {
+			match = user
+			found = true
+		}
+	}
+
+	return match, found
+}
+
+// Update overwrites the mutable fields of the user with the given id.
+func (r *InMemoryRepository) Update(ctx context.Context, id string, user User) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.data[id]; !exists {
+		return User{}, fmt.Errorf("user %s not found", id)
+	}
+
+	user.ID = id
+	r.data[id] = user
+	return user, nil
+}
+
+// Upsert creates user if it has no ID, or overwrites the existing record
+// with that ID otherwise.
+func (r *InMemoryRepository) Upsert(ctx context.Context, user User) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = strconv.Itoa(r.nextID)
+		r.nextID++
+	}
+	r.data[user.ID] = user
+	return user, nil
+}
+
+// CreatePasswordResetToken generates and stores a password reset token for
+// the user with the given email, valid until time.Now()+ttl.
+func (r *InMemoryRepository) CreatePasswordResetToken(ctx context.Context, email types.Email, ttl time.Duration) (User, string, bool, error) {
+	existing, exists := r.GetByEmail(email)
+	if !exists {
+		return User{}, "", false, nil
+	}
+
+	token, err := GenerateVerificationToken()
+	if err != nil {
+		return User{}, "", true, fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	existing.PasswordResetToken = token
+	existing.PasswordResetExpiry = time.Now().Add(ttl).Format(time.RFC3339)
+
+	updated, err := r.Update(ctx, existing.ID, existing)
+	if err != nil {
+		return User{}, "", true, err
+	}
+	return updated, token, true, nil
+}
+
+// ConsumePasswordResetToken looks up the user holding token, checks it
+// hasn't expired, and clears it so it cannot be reused.
+func (r *InMemoryRepository) ConsumePasswordResetToken(ctx context.Context, token string) (User, bool, error) {
+	target, exists := r.GetByResetToken(token)
+	if !exists {
+		return User{}, false, nil
+	}
+
+	expiry, err := time.Parse(time.RFC3339, target.PasswordResetExpiry)
+	if err != nil || time.Now().After(expiry) {
+		return User{}, false, nil
+	}
+
+	target.PasswordResetToken = ""
+	target.PasswordResetExpiry = ""
+
+	updated, err := r.Update(ctx, target.ID, target)
+	if err != nil {
+		return User{}, false, err
+	}
+	return updated, true, nil
+}
+
+// UpdatePassword overwrites user's password with hashedPassword and
+// persists it.
+func (r *InMemoryRepository) UpdatePassword(ctx context.Context, user User, hashedPassword string) (User, error) {
+	user.Password = hashedPassword
+	return r.Update(ctx, user.ID, user)
+}
+
 // AirtableRepository wraps a Repository and adds Airtable persistence
 type AirtableRepository struct {
 	repo           Repository
 	airtableClient *airtable.Client
 	airtableTable  string
+	replicator     replication.Emitter
 }
 
 // NewAirtableRepository creates a repository that syncs to Airtable
@@ -123,6 +371,25 @@ func NewAirtableRepository(repo Repository, airtableClient *airtable.Client, air
 	}
 }
 
+// SetReplicator enables mirroring Create/Delete mutations to any
+// replication policy registered for the "user" table. Without a
+// replicator, those methods still work but replicate nowhere.
+func (r *AirtableRepository) SetReplicator(emitter replication.Emitter) {
+	r.replicator = emitter
+}
+
+// emitReplication queues a replication event if a replicator has been
+// configured, logging (but not failing the calling operation on) any error.
+func (r *AirtableRepository) emitReplication(ctx context.Context, action replication.Action, resourceID string) {
+	if r.replicator == nil {
+		return
+	}
+
+	if err := r.replicator.Emit(ctx, replication.Event{Table: "user", Action: action, ResourceID: resourceID}); err != nil {
+		log.Printf("Failed to emit user replication event for %s: %v", resourceID, err)
+	}
+}
+
 // List returns all users from Airtable, falling back to underlying repository
 func (r *AirtableRepository) List() []User {
 	records, err := r.airtableClient.ListRecords(context.Background(), r.airtableTable, nil)
@@ -171,6 +438,7 @@ func (r *AirtableRepository) Create(ctx context.Context, user User) (User, error
 	// Update the created user with Airtable ID
 	created.ID = airtableRecord.ID
 	log.Printf("User saved to Airtable successfully with ID: %s", airtableRecord.ID)
+	r.emitReplication(ctx, replication.ActionCreate, created.ID)
 	return created, nil
 }
 
@@ -187,28 +455,160 @@ func (r *AirtableRepository) Delete(id string) bool {
 		log.Printf("Failed to delete Airtable record for user %s: %v", id, err)
 	}
 
+	r.emitReplication(context.Background(), replication.ActionDelete, id)
+
 	return true
 }
 
+// Get retrieves a user by ID from the underlying repository
+func (r *AirtableRepository) Get(id string) (User, bool) {
+	return r.repo.Get(id)
+}
+
 // GetByEmail retrieves a user by email from the underlying repository
-func (r *AirtableRepository) GetByEmail(email string) (User, bool) {
+func (r *AirtableRepository) GetByEmail(email types.Email) (User, bool) {
 	return r.repo.GetByEmail(email)
 }
 
+// GetByVerificationToken retrieves a user by email verification token from
+// the underlying repository, as GetByEmail does.
+func (r *AirtableRepository) GetByVerificationToken(token string) (User, bool) {
+	return r.repo.GetByVerificationToken(token)
+}
+
+// GetByResetToken retrieves a user by password reset token from the
+// underlying repository, as GetByEmail does.
+func (r *AirtableRepository) GetByResetToken(token string) (User, bool) {
+	return r.repo.GetByResetToken(token)
+}
+
+// Update persists changed fields to the underlying repository and Airtable.
+func (r *AirtableRepository) Update(ctx context.Context, id string, user User) (User, error) {
+	updated, err := r.repo.Update(ctx, id, user)
+	if err != nil {
+		return User{}, err
+	}
+
+	airtableFields := updated.ToAirtableFieldsForUpdate()
+	airtableRecord, err := r.airtableClient.UpdateRecordPartial(ctx, r.airtableTable, id, airtableFields)
+	if err != nil {
+		log.Printf("Failed to update user in Airtable: %v", err)
+		return updated, nil
+	}
+
+	mapped, err := mapAirtableRecord(airtableRecord)
+	if err != nil {
+		return updated, nil
+	}
+	return mapped, nil
+}
+
+// Upsert creates user if it has no ID, or partially updates the existing
+// Airtable record with that ID otherwise.
+func (r *AirtableRepository) Upsert(ctx context.Context, user User) (User, error) {
+	if user.ID == "" {
+		return r.Create(ctx, user)
+	}
+
+	airtableFields := user.ToAirtableFieldsForUpdate()
+	airtableRecord, err := r.airtableClient.UpdateRecordPartial(ctx, r.airtableTable, user.ID, airtableFields)
+	if err != nil {
+		log.Printf("Failed to upsert user in Airtable: %v", err)
+		return user, nil
+	}
+
+	updated, err := mapAirtableRecord(airtableRecord)
+	if err != nil {
+		return user, nil
+	}
+	return updated, nil
+}
+
+// Search filters, sorts, and paginates the full user list fetched from
+// Airtable (falling back to the underlying repository, same as List).
+func (r *AirtableRepository) Search(ctx context.Context, filter UserFilter) ([]User, int, error) {
+	result, total := filterSortPaginate(r.List(), filter)
+	return result, total, nil
+}
+
+// CreatePasswordResetToken generates and stores a password reset token for
+// the user with the given email, valid until time.Now()+ttl.
+func (r *AirtableRepository) CreatePasswordResetToken(ctx context.Context, email types.Email, ttl time.Duration) (User, string, bool, error) {
+	existing, exists := r.GetByEmail(email)
+	if !exists {
+		return User{}, "", false, nil
+	}
+
+	token, err := GenerateVerificationToken()
+	if err != nil {
+		return User{}, "", true, fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	existing.PasswordResetToken = token
+	existing.PasswordResetExpiry = time.Now().Add(ttl).Format(time.RFC3339)
+
+	updated, err := r.Update(ctx, existing.ID, existing)
+	if err != nil {
+		return User{}, "", true, err
+	}
+	return updated, token, true, nil
+}
+
+// ConsumePasswordResetToken looks up the user holding token, checks it
+// hasn't expired, and clears it so it cannot be reused.
+func (r *AirtableRepository) ConsumePasswordResetToken(ctx context.Context, token string) (User, bool, error) {
+	target, exists := r.GetByResetToken(token)
+	if !exists {
+		return User{}, false, nil
+	}
+
+	expiry, err := time.Parse(time.RFC3339, target.PasswordResetExpiry)
+	if err != nil || time.Now().After(expiry) {
+		return User{}, false, nil
+	}
+
+	target.PasswordResetToken = ""
+	target.PasswordResetExpiry = ""
+
+	updated, err := r.Update(ctx, target.ID, target)
+	if err != nil {
+		return User{}, false, err
+	}
+	return updated, true, nil
+}
+
+// UpdatePassword overwrites user's password with hashedPassword and
+// persists it.
+func (r *AirtableRepository) UpdatePassword(ctx context.Context, user User, hashedPassword string) (User, error) {
+	user.Password = hashedPassword
+	return r.Update(ctx, user.ID, user)
+}
+
 func mapAirtableRecord(record airtable.Record) (User, error) {
 	role := getStringField(record.Fields, FieldRole)
 	if role == "" {
 		role = RoleUser // Default role
 	}
+	status := getStringField(record.Fields, FieldStatus)
+	if status == "" {
+		status = StatusActive // Default for records created before Status existed
+	}
 	return User{
-		ID:       record.ID,
-		Email:    getStringField(record.Fields, FieldEmail),
-		Password: getStringField(record.Fields, FieldPassword),
-		Role:     role,
+		ID:                     record.ID,
+		Email:                  types.Email(getStringField(record.Fields, FieldEmail)),
+		Password:               getStringField(record.Fields, FieldPassword),
+		Role:                   role,
+		Status:                 status,
+		EmailVerificationToken: getStringField(record.Fields, FieldEmailVerificationToken),
+		OAuthIdentities:        decodeOAuthIdentities(getStringField(record.Fields, FieldOAuthIdentities)),
+		PasswordResetToken:     getStringField(record.Fields, FieldPasswordResetToken),
+		PasswordResetExpiry:    getStringField(record.Fields, FieldPasswordResetExpiry),
+		TOTPSecret:             getStringField(record.Fields, FieldTOTPSecret),
+		TOTPEnabled:            getStringField(record.Fields, FieldTOTPEnabled) == "true",
+		TOTPRecoveryCodes:      decodeRecoveryCodes(getStringField(record.Fields, FieldTOTPRecoveryCodes)),
 	}, nil
 }
 
 func escapeAirtableFormulaValue(value string) string {
 	return strings.ReplaceAll(value, "'", "''")
 }
-
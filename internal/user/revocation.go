@@ -0,0 +1,20 @@
+package user
+
+// Revoker reports whether an access token's jti has been revoked - by its
+// own holder logging out, or an admin forcing a logout (see
+// RevokeUserSessionsHandler) - so ValidateToken can reject it immediately
+// instead of waiting out the rest of its lifetime.
+type Revoker interface {
+	IsRevoked(jti string) bool
+}
+
+// revoker, when set, lets ValidateToken reject tokens whose jti has been
+// revoked. Without a registered Revoker, access tokens remain valid for
+// their full lifetime once issued, same as before this existed - the same
+// optional-plugin shape RegisterJWKSVerifier already uses.
+var revoker Revoker
+
+// RegisterRevoker wires in access-token revocation checks.
+func RegisterRevoker(r Revoker) {
+	revoker = r
+}
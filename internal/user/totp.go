@@ -0,0 +1,335 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lam-phuong-api/internal/refreshtoken"
+	"lam-phuong-api/internal/response"
+	"lam-phuong-api/internal/totp"
+)
+
+// totpIssuer names this service in the otpauth:// URI and authenticator app
+// entry.
+const totpIssuer = "Lam Phuong API"
+
+// mfaChallengeTTL bounds how long the mfa_token from LoginHandler's first
+// step is valid before /auth/2fa/login must be retried from scratch.
+const mfaChallengeTTL = 5 * time.Minute
+
+// recoveryCodeCount is how many one-time recovery codes are generated at
+// enrollment, for use if the authenticator device is lost.
+const recoveryCodeCount = 10
+
+type verify2FAPayload struct {
+	Code string `json:"code" binding:"required,len=6"`
+}
+
+type mfaLoginPayload struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// Enroll2FAHandler godoc
+// @Summary      Begin TOTP enrollment
+// @Description  Generates a new TOTP secret for the authenticated user and returns it as a base32 string, an otpauth:// URI, and a QR code PNG (base64 data URI) for scanning into an authenticator app. 2FA is not enforced until POST /auth/2fa/verify confirms a code from this secret.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response  "Enrollment secret generated"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500  {object}  response.ErrorResponse  "Internal server error"
+// @Router       /auth/2fa/enroll [post]
+func (h *Handler) Enroll2FAHandler(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	currentUser, exists := h.repo.Get(userID.(string))
+	if !exists {
+		response.NotFound(c, "User")
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		response.InternalError(c, "Failed to generate TOTP secret")
+		return
+	}
+
+	otpauthURL := totp.OTPAuthURL(totpIssuer, string(currentUser.Email), secret)
+
+	qrPNG, err := totp.GenerateQRCodePNG(otpauthURL)
+	if err != nil {
+		response.InternalError(c, "Failed to generate QR code: "+err.Error())
+		return
+	}
+
+	// Stored immediately so POST /auth/2fa/verify can validate against it,
+	// but TOTPEnabled stays false until that call succeeds.
+	currentUser.TOTPSecret = secret
+	currentUser.TOTPEnabled = false
+	if _, err := h.repo.Update(c.Request.Context(), currentUser.ID, currentUser); err != nil {
+		response.InternalError(c, "Failed to store TOTP secret: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"secret":           secret,
+		"otpauth_url":      otpauthURL,
+		"qr_code_data_uri": "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrPNG),
+	}, "Scan the QR code with an authenticator app, then confirm with POST /auth/2fa/verify")
+}
+
+// Verify2FAHandler godoc
+// @Summary      Confirm TOTP enrollment
+// @Description  Validates a code from the secret generated by POST /auth/2fa/enroll and, if correct, enables 2FA enforcement on login and returns 10 one-time recovery codes (shown only this once).
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        credentials  body      verify2FAPayload  true  "6-digit code from the authenticator app"
+// @Success      200  {object}  response.Response  "2FA enabled"
+// @Failure      400  {object}  response.ErrorResponse  "Invalid code or no enrollment in progress"
+// @Failure      401  {object}  response.ErrorResponse  "Unauthorized"
+// @Failure      500  {object}  response.ErrorResponse  "Internal server error"
+// @Router       /auth/2fa/verify [post]
+func (h *Handler) Verify2FAHandler(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var payload verify2FAPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	currentUser, exists := h.repo.Get(userID.(string))
+	if !exists {
+		response.NotFound(c, "User")
+		return
+	}
+
+	if currentUser.TOTPSecret == "" {
+		response.BadRequest(c, "No 2FA enrollment in progress; call POST /auth/2fa/enroll first", nil)
+		return
+	}
+
+	if !totp.Validate(currentUser.TOTPSecret, payload.Code) {
+		response.BadRequest(c, "Invalid or expired code", nil)
+		return
+	}
+
+	recoveryCodes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		response.InternalError(c, "Failed to generate recovery codes")
+		return
+	}
+
+	currentUser.TOTPEnabled = true
+	currentUser.TOTPRecoveryCodes = hashedCodes
+	if _, err := h.repo.Update(c.Request.Context(), currentUser.ID, currentUser); err != nil {
+		response.InternalError(c, "Failed to enable 2FA: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"totp_enabled":   true,
+		"recovery_codes": recoveryCodes,
+	}, "2FA enabled. Store these recovery codes somewhere safe; they will not be shown again.")
+}
+
+// MFALoginHandler godoc
+// @Summary      Complete a 2FA-challenged login
+// @Description  Exchanges the mfa_token from LoginHandler plus a 6-digit TOTP code (or a recovery code) for an access JWT and refresh token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      mfaLoginPayload  true  "MFA challenge token and TOTP or recovery code"
+// @Success      200  {object}  user.TokenResponseWrapper  "Login successful"
+// @Failure      400  {object}  response.ErrorResponse  "Validation error"
+// @Failure      401  {object}  response.ErrorResponse  "Invalid or expired challenge, or invalid code"
+// @Router       /auth/2fa/login [post]
+func (h *Handler) MFALoginHandler(c *gin.Context) {
+	var payload mfaLoginPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		response.ValidationError(c, "Invalid request data", map[string]interface{}{
+			"validation_error": err.Error(),
+		})
+		return
+	}
+
+	userID, err := h.verifyMFAChallenge(payload.MFAToken)
+	if err != nil {
+		response.InvalidToken(c, "Invalid or expired MFA challenge: "+err.Error())
+		return
+	}
+
+	targetUser, exists := h.repo.Get(userID)
+	if !exists || !targetUser.TOTPEnabled {
+		response.InvalidToken(c, "Invalid MFA challenge")
+		return
+	}
+
+	switch {
+	case payload.Code != "":
+		if !totp.Validate(targetUser.TOTPSecret, payload.Code) {
+			response.InvalidAuth(c, "Invalid code")
+			return
+		}
+	case payload.RecoveryCode != "":
+		remaining, consumed := consumeRecoveryCode(targetUser.TOTPRecoveryCodes, payload.RecoveryCode)
+		if !consumed {
+			response.InvalidAuth(c, "Invalid recovery code")
+			return
+		}
+		targetUser.TOTPRecoveryCodes = remaining
+		if _, err := h.repo.Update(c.Request.Context(), targetUser.ID, targetUser); err != nil {
+			response.InternalError(c, "Failed to consume recovery code: "+err.Error())
+			return
+		}
+	default:
+		response.ValidationError(c, "Either code or recovery_code is required", nil)
+		return
+	}
+
+	accessToken, jti, err := GenerateToken(targetUser, h.jwtSecret, h.tokenExpiry)
+	if err != nil {
+		response.InternalError(c, "Failed to generate token")
+		return
+	}
+
+	refreshToken, err := h.issueRefreshToken(c.Request.Context(), c, targetUser.ID, jti, time.Now().Add(h.tokenExpiry))
+	if err != nil {
+		log.Printf("Failed to issue refresh token: %v", err)
+	}
+
+	targetUser.Password = ""
+	tokenResp := TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(h.tokenExpiry.Seconds()),
+		User:         targetUser,
+		RefreshToken: refreshToken,
+	}
+	if refreshToken != "" {
+		tokenResp.RefreshExpiresIn = int64(refreshTokenTTL.Seconds())
+	}
+	response.Success(c, http.StatusOK, tokenResp, "Login successful")
+}
+
+// signMFAChallenge produces a self-contained, short-lived challenge token
+// identifying userID, signed the same way OAuthLogin's state nonce is
+// (see oauth.go's signPayload), so MFALoginHandler can verify it without
+// server-side session storage.
+func (h *Handler) signMFAChallenge(userID string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	payload := fmt.Sprintf("%s|%d|%s", userID, time.Now().Add(mfaChallengeTTL).Unix(), hex.EncodeToString(nonce))
+	sig := h.signPayload(payload)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload + "|" + sig)), nil
+}
+
+func (h *Handler) verifyMFAChallenge(token string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("malformed challenge")
+	}
+
+	parts := strings.SplitN(string(decoded), "|", 4)
+	if len(parts) != 4 {
+		return "", fmt.Errorf("malformed challenge")
+	}
+
+	payload := strings.Join(parts[:3], "|")
+	wantedSig := h.signPayload(payload)
+	if !hmac.Equal([]byte(parts[3]), []byte(wantedSig)) {
+		return "", fmt.Errorf("challenge signature mismatch")
+	}
+
+	var expiresAt int64
+	if _, err := fmt.Sscanf(parts[1], "%d", &expiresAt); err != nil {
+		return "", fmt.Errorf("malformed challenge expiry")
+	}
+	if time.Now().Unix() > expiresAt {
+		return "", fmt.Errorf("challenge expired")
+	}
+
+	return parts[0], nil
+}
+
+// requireFreshMFA checks X-MFA-Code against actingUser's TOTP secret when
+// 2FA is enabled for that user, for use by destructive endpoints
+// (ChangeUserPassword, DeleteUser). Returns true when the caller may
+// proceed; on false it has already written the error response.
+func (h *Handler) requireFreshMFA(c *gin.Context, actingUser User) bool {
+	if !actingUser.TOTPEnabled {
+		return true
+	}
+
+	code := c.GetHeader("X-MFA-Code")
+	if code == "" {
+		response.Forbidden(c, "This action requires a fresh 2FA code; set the X-MFA-Code header")
+		return false
+	}
+	if !totp.Validate(actingUser.TOTPSecret, code) {
+		response.Forbidden(c, "Invalid or expired 2FA code")
+		return false
+	}
+	return true
+}
+
+// generateRecoveryCodes creates recoveryCodeCount random one-time recovery
+// codes, returning both the plaintext values (shown to the user exactly
+// once) and their hashes (what gets persisted).
+func generateRecoveryCodes() (plain []string, hashed []string, err error) {
+	plain = make([]string, recoveryCodeCount)
+	hashed = make([]string, recoveryCodeCount)
+	for i := range plain {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		raw := strings.ToUpper(hex.EncodeToString(b))
+		code := raw[:5] + "-" + raw[5:]
+		plain[i] = code
+		hashed[i] = refreshtoken.Hash(code)
+	}
+	return plain, hashed, nil
+}
+
+// consumeRecoveryCode reports whether code matches one of hashedCodes and,
+// if so, returns the list with that entry removed (each recovery code is
+// single-use).
+func consumeRecoveryCode(hashedCodes []string, code string) (remaining []string, consumed bool) {
+	target := refreshtoken.Hash(strings.ToUpper(strings.TrimSpace(code)))
+	remaining = make([]string, 0, len(hashedCodes))
+	for _, hashedCode := range hashedCodes {
+		if !consumed && hashedCode == target {
+			consumed = true
+			continue
+		}
+		remaining = append(remaining, hashedCode)
+	}
+	return remaining, consumed
+}